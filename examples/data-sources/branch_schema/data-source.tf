@@ -0,0 +1,11 @@
+# Read the live schema of the main branch of a database.
+data "xata_branch_schema" "inventory" {
+  workspace_id = "markspace-a1b2c3"
+  database     = "inventory"
+  branch       = "main"
+}
+
+# Names of the tables, for instance to expose each of them behind a gateway.
+output "tables" {
+  value = data.xata_branch_schema.inventory.tables[*].name
+}
@@ -0,0 +1,7 @@
+# List the preview branches labelled as stale, for instance to clean them up.
+data "xata_branches" "stale_previews" {
+  workspace_id = "markspace-a1b2c3"
+  database     = "inventory"
+  name_prefix  = "pr-"
+  label        = "stale"
+}
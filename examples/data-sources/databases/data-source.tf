@@ -0,0 +1,4 @@
+# List the databases of a workspace, the provider workspace_id when unset.
+data "xata_databases" "markspace" {
+  workspace_id = "markspace-a1b2c3"
+}
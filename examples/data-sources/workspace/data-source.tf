@@ -0,0 +1,4 @@
+# Look a workspace up by slug, or by id or name.
+data "xata_workspace" "markspace" {
+  slug = "markspace"
+}
@@ -1,2 +1,10 @@
 # List all workspaces.
 data "xata_workspaces" "all" {}
+
+# List the workspaces on the free tier owned by the caller whose slug starts with team-.
+data "xata_workspaces" "team" {
+  slug_prefix = "team-"
+  role        = "owner"
+  plan        = "free"
+  sort_by     = "slug"
+}
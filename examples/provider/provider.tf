@@ -2,3 +2,15 @@
 provider "xata" {
   apikey = "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX"
 }
+
+# Credentials of a profile of the Xata CLI, stored by `xata auth login --profile staging`
+provider "xata" {
+  alias   = "staging"
+  profile = "staging"
+}
+
+# API key mounted as a file by the CI system
+provider "xata" {
+  alias       = "ci"
+  apikey_file = "/run/secrets/xata_api_key"
+}
@@ -0,0 +1,18 @@
+resource "xata_branch" "preview" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "pr-42"
+  from         = "main"
+
+  metadata = {
+    repository = "github.com/markspace/inventory"
+    branch     = "feature/pr-42"
+    stage      = "preview"
+    labels     = ["preview"]
+  }
+
+  # Copying a large parent branch can take a while.
+  timeouts {
+    create = "45m"
+  }
+}
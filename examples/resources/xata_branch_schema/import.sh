@@ -0,0 +1,2 @@
+# Branch schema can be imported by specifying the workspace identifier, the database name and the branch name.
+terraform import xata_branch_schema.main markspace-a1b2c3/inventory:main
@@ -0,0 +1,7 @@
+# Keep the schema dumped with `xata schema dump` in git as the source of truth.
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = "main"
+  schema       = file("${path.module}/schema.json")
+}
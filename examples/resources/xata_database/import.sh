@@ -0,0 +1,2 @@
+# Database can be imported by specifying the workspace identifier and the database name.
+terraform import xata_database.inventory markspace-a1b2c3/inventory
@@ -0,0 +1,11 @@
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id   = xata_workspace.markspace.id
+  name           = "inventory"
+  region         = "us-east-1"
+  default_branch = "main"
+  ui_color       = "xata-orange"
+}
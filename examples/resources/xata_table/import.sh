@@ -0,0 +1,2 @@
+# Table can be imported by specifying the workspace identifier, the database name, the branch name and the table name.
+terraform import xata_table.items markspace-a1b2c3/inventory:main/items
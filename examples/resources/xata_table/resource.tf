@@ -0,0 +1,43 @@
+resource "xata_table" "items" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = "main"
+  name         = "items"
+
+  columns = [
+    {
+      name          = "title"
+      type          = "string"
+      not_null      = true
+      default_value = "untitled"
+    },
+    {
+      name   = "sku"
+      type   = "string"
+      unique = true
+    },
+    {
+      name       = "supplier"
+      type       = "link"
+      link_table = "suppliers"
+    },
+    {
+      name             = "embedding"
+      type             = "vector"
+      vector_dimension = 1536
+    },
+    {
+      name                       = "photo"
+      type                       = "file"
+      file_default_public_access = true
+    },
+    {
+      name = "dimensions"
+      type = "object"
+    },
+    {
+      name = "dimensions.width"
+      type = "float"
+    },
+  ]
+}
@@ -0,0 +1,2 @@
+# Workspace invite can be imported by specifying the workspace identifier and the invite identifier.
+terraform import xata_workspace_invite.contractor markspace-a1b2c3/inv_a1b2c3d4e5
@@ -0,0 +1,12 @@
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace_invite" "contractor" {
+  workspace_id = xata_workspace.markspace.id
+  email        = "contractor@example.com"
+  role         = "maintainer"
+
+  # Change this value to send the invite email again.
+  resend_trigger = "1"
+}
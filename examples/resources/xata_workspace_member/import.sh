@@ -0,0 +1,2 @@
+# Workspace member can be imported by specifying the workspace identifier and the user identifier.
+terraform import xata_workspace_member.alice markspace-a1b2c3/usr_a1b2c3d4e5
@@ -0,0 +1,9 @@
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace_member" "alice" {
+  workspace_id = xata_workspace.markspace.id
+  user_id      = "usr_a1b2c3d4e5"
+  role         = "maintainer"
+}
@@ -0,0 +1,498 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &branchResource{}
+	_ resource.ResourceWithConfigure   = &branchResource{}
+	_ resource.ResourceWithImportState = &branchResource{}
+	_ resource.ResourceWithModifyPlan  = &branchResource{}
+)
+
+// NewBranchResource is a helper function to simplify the provider implementation.
+func NewBranchResource() resource.Resource {
+	return &branchResource{}
+}
+
+// branchResource is the resource implementation.
+type branchResource struct {
+	client *xataClient
+}
+
+// branchResourceModel maps the resource schema data.
+type branchResourceModel struct {
+	Id          types.String         `tfsdk:"id"`
+	WorkspaceId types.String         `tfsdk:"workspace_id"`
+	Database    types.String         `tfsdk:"database"`
+	Name        types.String         `tfsdk:"name"`
+	From        types.String         `tfsdk:"from"`
+	Region      types.String         `tfsdk:"region"`
+	CreatedAt   types.String         `tfsdk:"created_at"`
+	Metadata    *branchMetadataModel `tfsdk:"metadata"`
+	LastUpdated types.String         `tfsdk:"last_updated"`
+	Timeouts    timeouts.Value       `tfsdk:"timeouts"`
+}
+
+// branchMetadataModel maps branch metadata schema data.
+type branchMetadataModel struct {
+	Repository types.String   `tfsdk:"repository"`
+	Branch     types.String   `tfsdk:"branch"`
+	Stage      types.String   `tfsdk:"stage"`
+	Labels     []types.String `tfsdk:"labels"`
+}
+
+// Metadata returns the resource type name.
+func (r *branchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branch"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *branchResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *branchResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a branch of a database.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the branch in the form workspace_id/database:name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace the database belongs to. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Description: "Name of the database the branch belongs to. Defaults to the database of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the branch.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"from": schema.StringAttribute{
+				Description: "Name of the parent branch to copy the schema from.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database of the branch is hosted.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Description: "Timestamp of the creation of the branch.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": schema.SingleNestedAttribute{
+				Description: "Git metadata attached to the branch.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"repository": schema.StringAttribute{
+						Description: "Repository the branch is associated with.",
+						Optional:    true,
+					},
+					"branch": schema.StringAttribute{
+						Description: "Git branch the branch is associated with.",
+						Optional:    true,
+					},
+					"stage": schema.StringAttribute{
+						Description: "Deployment stage of the branch.",
+						Optional:    true,
+					},
+					"labels": schema.ListAttribute{
+						Description: "Labels attached to the branch.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the branch.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ModifyPlan sets the attributes left unset to the provider defaults.
+func (r *branchResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id", "database")
+}
+
+// Create a new resource.
+func (r *branchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan branchResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resolve the workspace API serving the database
+	region, found, err := r.client.databaseRegion(ctx, plan.WorkspaceId.ValueString(), plan.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata branch",
+			fmt.Sprintf("Could not read database %q, unexpected error: %s", plan.Database.ValueString(), err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Error creating Xata branch",
+			fmt.Sprintf("Database %q does not exist in workspace %q.", plan.Database.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.Region = types.StringValue(region)
+
+	branchClient, ok := workspaceClient[xata.BranchClient](r.client, plan.WorkspaceId.ValueString(), region, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Generate API request body from plan
+	branchRequest := xata.CreateBranchRequest{
+		BranchName:   plan.Name.ValueString(),
+		DatabaseName: xata.String(plan.Database.ValueString()),
+		Payload: &xata.CreateBranchRequestPayload{
+			CreateBranchRequestFrom: plan.From.ValueStringPointer(),
+		},
+	}
+	if plan.Metadata != nil {
+		metadata := plan.Metadata.toAPI()
+		branchRequest.Payload.Metadata = &xata.BranchMetadataWS{
+			Repository: metadata.Repository,
+			Branch:     metadata.Branch,
+			Stage:      metadata.Stage,
+			Labels:     metadata.Labels,
+		}
+	}
+
+	// Create new branch
+	_, err = branchClient.Create(ctx, branchRequest)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata branch",
+			fmt.Sprintf("Could not create branch, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Populate Computed attribute values from the created branch
+	err = r.readBranch(ctx, branchClient, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata branch",
+			fmt.Sprintf("Could not read branch after creation, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *branchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state branchResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported branches do not know their region yet
+	if state.Region.IsNull() || state.Region.ValueString() == "" {
+		region, found, err := r.client.databaseRegion(ctx, state.WorkspaceId.ValueString(), state.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Xata branch",
+				fmt.Sprintf("Could not read database %q, unexpected error: %s", state.Database.ValueString(), err.Error()),
+			)
+			return
+		}
+		if !found {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		state.Region = types.StringValue(region)
+	}
+
+	branchClient, ok := workspaceClient[xata.BranchClient](r.client, state.WorkspaceId.ValueString(), state.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Get existing branch
+	err := r.readBranch(ctx, branchClient, &state)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata branch",
+			fmt.Sprintf("Could not read branch, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Return branch info
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *branchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan branchResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Replace the branch metadata, only the metadata can change in place
+	var metadata branchMetadata
+	if plan.Metadata != nil {
+		metadata = plan.Metadata.toAPI()
+	}
+	workspaceURL := r.client.workspaceURL(plan.WorkspaceId.ValueString(), plan.Region.ValueString())
+	err := r.client.api.UpdateBranchMetadata(ctx, workspaceURL, plan.Database.ValueString(), plan.Name.ValueString(), metadata)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Xata Branch",
+			fmt.Sprintf("Could not update branch metadata, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	branchClient, ok := workspaceClient[xata.BranchClient](r.client, plan.WorkspaceId.ValueString(), plan.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Populate Computed attribute values from the updated branch
+	err = r.readBranch(ctx, branchClient, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata branch",
+			fmt.Sprintf("Could not read branch after update, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *branchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state branchResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	branchClient, ok := workspaceClient[xata.BranchClient](r.client, state.WorkspaceId.ValueString(), state.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Delete branch
+	_, err := branchClient.Delete(ctx, xata.BranchRequest{
+		DatabaseName: xata.String(state.Database.ValueString()),
+		BranchName:   state.Name.ValueString(),
+	})
+	// A branch deleted outside Terraform is already gone
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Xata Branch",
+			fmt.Sprintf("Could not delete branch, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+}
+
+func (r *branchResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID, database name and branch name
+	workspaceID, dbBranchName, _ := strings.Cut(req.ID, "/")
+	database, name, _ := strings.Cut(dbBranchName, ":")
+	if workspaceID == "" || database == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/database:branch. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// readBranch fetches the branch named in model and maps the API response
+// onto it.
+func (r *branchResource) readBranch(ctx context.Context, branchClient xata.BranchClient, model *branchResourceModel) error {
+	branch, err := branchClient.GetDetails(ctx, xata.BranchRequest{
+		DatabaseName: xata.String(model.Database.ValueString()),
+		BranchName:   model.Name.ValueString(),
+	})
+	if err != nil {
+		return err
+	}
+
+	model.Id = types.StringValue(fmt.Sprintf("%s/%s:%s", model.WorkspaceId.ValueString(), branch.DatabaseName, branch.BranchName))
+	model.CreatedAt = types.StringValue(branch.CreatedAt.Format(time.RFC3339))
+	// Branches created from a parent report it, which fills in from on
+	// import. Keep the known value of branches which do not.
+	if branch.StartedFrom != nil && branch.StartedFrom.BranchName != "" {
+		model.From = types.StringValue(string(branch.StartedFrom.BranchName))
+	}
+
+	model.Metadata = nil
+	if branch.Metadata != nil {
+		model.Metadata = newBranchMetadataModel(branchMetadata{
+			Repository: branch.Metadata.Repository,
+			Branch:     branch.Metadata.Branch,
+			Stage:      branch.Metadata.Stage,
+			Labels:     branch.Metadata.Labels,
+		})
+	}
+
+	return nil
+}
+
+// newBranchMetadataModel maps branch metadata to its schema data. It
+// returns nil when no metadata is set.
+func newBranchMetadataModel(metadata branchMetadata) *branchMetadataModel {
+	model := &branchMetadataModel{
+		Repository: types.StringPointerValue(metadata.Repository),
+		Branch:     types.StringPointerValue(metadata.Branch),
+		Stage:      types.StringPointerValue(metadata.Stage),
+	}
+	// Keep an empty list of labels apart from unset labels, so that a
+	// configured labels = [] does not show a diff after every refresh
+	if metadata.Labels != nil {
+		model.Labels = make([]types.String, 0, len(*metadata.Labels))
+		for _, label := range *metadata.Labels {
+			model.Labels = append(model.Labels, types.StringValue(label))
+		}
+	}
+
+	if model.Repository.IsNull() && model.Branch.IsNull() && model.Stage.IsNull() && model.Labels == nil {
+		return nil
+	}
+
+	return model
+}
+
+// toAPI converts branch metadata schema data to the API representation.
+func (m *branchMetadataModel) toAPI() branchMetadata {
+	metadata := branchMetadata{
+		Repository: m.Repository.ValueStringPointer(),
+		Branch:     m.Branch.ValueStringPointer(),
+		Stage:      m.Stage.ValueStringPointer(),
+	}
+	if m.Labels != nil {
+		labels := make([]string, 0, len(m.Labels))
+		for _, label := range m.Labels {
+			labels = append(labels, label.ValueString())
+		}
+		metadata.Labels = &labels
+	}
+
+	return metadata
+}
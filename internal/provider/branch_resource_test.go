@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBranchResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch" "preview" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "pr-42"
+  from         = xata_database.inventory.default_branch
+
+  metadata = {
+    repository = "github.com/markspace/inventory"
+    branch     = "feature/pr-42"
+    stage      = "preview"
+    labels     = ["preview"]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify created branch has Computed attributes filled.
+					resource.TestCheckResourceAttr("xata_branch.preview", "name", "pr-42"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "database", "inventory"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "from", "main"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "region", "us-east-1"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "metadata.stage", "preview"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "metadata.labels.#", "1"),
+					resource.TestCheckResourceAttrSet("xata_branch.preview", "id"),
+					resource.TestCheckResourceAttrSet("xata_branch.preview", "created_at"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xata_branch.preview",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The last_updated attribute is not returned by the Xata
+				// API, therefore there is no value for it during import.
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch" "preview" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "pr-42"
+  from         = xata_database.inventory.default_branch
+
+  metadata = {
+    repository = "github.com/markspace/inventory"
+    branch     = "feature/pr-42"
+    stage      = "staging"
+    labels     = ["preview", "stale"]
+  }
+}
+
+resource "xata_branch" "scratch" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "scratch"
+
+  metadata = {
+    labels = []
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_branch.preview", "metadata.stage", "staging"),
+					resource.TestCheckNoResourceAttr("xata_branch.scratch", "from"),
+					resource.TestCheckResourceAttr("xata_branch.scratch", "metadata.labels.#", "0"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "metadata.labels.#", "2"),
+					resource.TestCheckResourceAttr("xata_branch.preview", "metadata.labels.1", "stale"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestNewBranchMetadataModel(t *testing.T) {
+	if model := newBranchMetadataModel(branchMetadata{}); model != nil {
+		t.Errorf("newBranchMetadataModel() = %+v for unset metadata, want nil", model)
+	}
+
+	model := newBranchMetadataModel(branchMetadata{Labels: &[]string{}})
+	if model == nil || model.Labels == nil || len(model.Labels) != 0 {
+		t.Errorf("newBranchMetadataModel() = %+v for empty labels, want an empty list of labels", model)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// schemaChange is a single operation needed to move a branch schema to its
+// desired state.
+type schemaChange struct {
+	Description string
+	Operation   migrationOperation
+}
+
+// parseBranchSchema decodes a branch schema document and checks that its
+// tables and columns are well formed.
+func parseBranchSchema(document string) (*branchSchema, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+
+	var schema branchSchema
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+
+	tables := make(map[string]bool, len(schema.Tables))
+	for _, table := range schema.Tables {
+		if table.Name == "" {
+			return nil, fmt.Errorf("every table must have a name")
+		}
+		if tables[table.Name] {
+			return nil, fmt.Errorf("table %q is declared more than once", table.Name)
+		}
+		tables[table.Name] = true
+
+		if err := validateSchemaColumns(table.Name, table.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema, nil
+}
+
+// validateSchemaColumns checks the columns of a table or object column.
+func validateSchemaColumns(parent string, columns []schemaColumn) error {
+	names := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if column.Name == "" {
+			return fmt.Errorf("every column of %q must have a name", parent)
+		}
+		if names[column.Name] {
+			return fmt.Errorf("column %q of %q is declared more than once", column.Name, parent)
+		}
+		names[column.Name] = true
+
+		if _, ok := columnTypes[column.Type]; !ok {
+			return fmt.Errorf("column %q of %q has unsupported type %q", column.Name, parent, column.Type)
+		}
+		if column.Type == "link" && (column.Link == nil || column.Link.Table == "") {
+			return fmt.Errorf("link column %q of %q must set link.table", column.Name, parent)
+		}
+		if column.Type == "vector" && column.Vector == nil {
+			return fmt.Errorf("vector column %q of %q must set vector.dimension", column.Name, parent)
+		}
+		if err := validateSchemaColumns(parent+"."+column.Name, column.Columns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// canonicalBranchSchema returns a copy of schema with tables and columns
+// sorted by name, internal Xata columns removed and flags that are false
+// by default unset, so that equivalent schemas compare equal.
+func canonicalBranchSchema(schema *branchSchema) *branchSchema {
+	canonical := &branchSchema{Tables: []schemaTable{}}
+	for _, table := range schema.Tables {
+		canonical.Tables = append(canonical.Tables, schemaTable{
+			Name:    table.Name,
+			Columns: canonicalSchemaColumns(table.Columns),
+		})
+	}
+	sort.Slice(canonical.Tables, func(i, j int) bool {
+		return canonical.Tables[i].Name < canonical.Tables[j].Name
+	})
+
+	return canonical
+}
+
+func canonicalSchemaColumns(columns []schemaColumn) []schemaColumn {
+	var canonical []schemaColumn
+	for _, column := range columns {
+		if strings.HasPrefix(column.Name, "xata.") || strings.HasPrefix(column.Name, "xata_") {
+			continue
+		}
+
+		column.NotNull = trueOrNil(column.NotNull)
+		column.Unique = trueOrNil(column.Unique)
+		if column.File != nil {
+			column.File = &schemaColumnFile{DefaultPublicAccess: trueOrNil(column.File.DefaultPublicAccess)}
+		}
+		if column.FileMap != nil {
+			column.FileMap = &schemaColumnFile{DefaultPublicAccess: trueOrNil(column.FileMap.DefaultPublicAccess)}
+		}
+		column.Columns = canonicalSchemaColumns(column.Columns)
+
+		canonical = append(canonical, column)
+	}
+	sort.Slice(canonical, func(i, j int) bool {
+		return canonical[i].Name < canonical[j].Name
+	})
+
+	return canonical
+}
+
+func trueOrNil(value *bool) *bool {
+	if value == nil || !*value {
+		return nil
+	}
+	return value
+}
+
+// marshalBranchSchema encodes the canonical form of schema as indented JSON.
+func marshalBranchSchema(schema *branchSchema) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(canonicalBranchSchema(schema)); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// equalBranchSchemas reports whether two schemas are equivalent.
+func equalBranchSchemas(a *branchSchema, b *branchSchema) bool {
+	left, errLeft := marshalBranchSchema(a)
+	right, errRight := marshalBranchSchema(b)
+	return errLeft == nil && errRight == nil && left == right
+}
+
+// diffBranchSchemas returns the operations that turn the current schema into
+// the desired one. Tables are added first so that new link columns can
+// reference them, and removed last. Columns whose definition changed are
+// removed and added again because the Xata API cannot alter a column.
+func diffBranchSchemas(current *branchSchema, desired *branchSchema) []schemaChange {
+	current = canonicalBranchSchema(current)
+	desired = canonicalBranchSchema(desired)
+
+	existing := make(map[string]schemaTable, len(current.Tables))
+	for _, table := range current.Tables {
+		existing[table.Name] = table
+	}
+	wanted := make(map[string]bool, len(desired.Tables))
+	for _, table := range desired.Tables {
+		wanted[table.Name] = true
+	}
+
+	var addTables, removeColumns, addColumns, removeTables []schemaChange
+	for _, table := range desired.Tables {
+		previous, ok := existing[table.Name]
+		if !ok {
+			addTables = append(addTables, schemaChange{
+				Description: fmt.Sprintf("add table %s", table.Name),
+				Operation:   migrationOperation{"addTable": map[string]any{"table": table.Name}},
+			})
+		}
+
+		columns := make(map[string]schemaColumn, len(previous.Columns))
+		for _, column := range previous.Columns {
+			columns[column.Name] = column
+		}
+		declared := make(map[string]bool, len(table.Columns))
+		for _, column := range table.Columns {
+			declared[column.Name] = true
+
+			before, found := columns[column.Name]
+			if found && equalSchemaColumns(before, column) {
+				continue
+			}
+			if found {
+				removeColumns = append(removeColumns, schemaChange{
+					Description: fmt.Sprintf("remove column %s.%s (definition changed)", table.Name, column.Name),
+					Operation:   migrationOperation{"removeColumn": map[string]any{"table": table.Name, "column": column.Name}},
+				})
+			}
+			addColumns = append(addColumns, schemaChange{
+				Description: fmt.Sprintf("add column %s.%s (%s)", table.Name, column.Name, column.Type),
+				Operation:   migrationOperation{"addColumn": map[string]any{"table": table.Name, "column": column}},
+			})
+		}
+
+		if !ok {
+			continue
+		}
+		for _, column := range previous.Columns {
+			if !declared[column.Name] {
+				removeColumns = append(removeColumns, schemaChange{
+					Description: fmt.Sprintf("remove column %s.%s", table.Name, column.Name),
+					Operation:   migrationOperation{"removeColumn": map[string]any{"table": table.Name, "column": column.Name}},
+				})
+			}
+		}
+	}
+
+	for _, table := range current.Tables {
+		if !wanted[table.Name] {
+			removeTables = append(removeTables, schemaChange{
+				Description: fmt.Sprintf("remove table %s", table.Name),
+				Operation:   migrationOperation{"removeTable": map[string]any{"table": table.Name}},
+			})
+		}
+	}
+
+	changes := append(addTables, removeColumns...)
+	changes = append(changes, addColumns...)
+	return append(changes, removeTables...)
+}
+
+// equalSchemaColumns reports whether two canonical columns are equivalent.
+func equalSchemaColumns(a schemaColumn, b schemaColumn) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ basetypes.StringTypable                    = branchSchemaType{}
+	_ basetypes.StringValuableWithSemanticEquals = branchSchemaValue{}
+)
+
+// branchSchemaType is the type of branch schema documents. Documents
+// describing the same schema are semantically equal, so that the document in
+// state is kept when the live schema only differs from it in formatting,
+// order or defaults.
+type branchSchemaType struct {
+	basetypes.StringType
+}
+
+func (t branchSchemaType) Equal(o attr.Type) bool {
+	other, ok := o.(branchSchemaType)
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (t branchSchemaType) String() string {
+	return "branchSchemaType"
+}
+
+func (t branchSchemaType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return branchSchemaValue{StringValue: in}, nil
+}
+
+func (t branchSchemaType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := value.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+
+	return branchSchemaValue{StringValue: stringValue}, nil
+}
+
+func (t branchSchemaType) ValueType(_ context.Context) attr.Value {
+	return branchSchemaValue{}
+}
+
+// branchSchemaValue is a branch schema document.
+type branchSchemaValue struct {
+	basetypes.StringValue
+}
+
+// newBranchSchemaValue returns a known branch schema document.
+func newBranchSchemaValue(document string) branchSchemaValue {
+	return branchSchemaValue{StringValue: types.StringValue(document)}
+}
+
+func (v branchSchemaValue) Equal(o attr.Value) bool {
+	other, ok := o.(branchSchemaValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v branchSchemaValue) Type(_ context.Context) attr.Type {
+	return branchSchemaType{}
+}
+
+// StringSemanticEquals reports whether both documents describe the same
+// schema. Documents which do not parse are only equal to themselves.
+func (v branchSchemaValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(branchSchemaValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got %T.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	prior, err := parseBranchSchema(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	current, err := parseBranchSchema(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	return equalBranchSchemas(prior, current), diags
+}
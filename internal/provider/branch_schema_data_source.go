@@ -0,0 +1,256 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &branchSchemaDataSource{}
+	_ datasource.DataSourceWithConfigure = &branchSchemaDataSource{}
+)
+
+// branchSchemaDataSourceModel maps the data source schema data.
+type branchSchemaDataSourceModel struct {
+	Id          types.String             `tfsdk:"id"`
+	WorkspaceId types.String             `tfsdk:"workspace_id"`
+	Database    types.String             `tfsdk:"database"`
+	Branch      types.String             `tfsdk:"branch"`
+	Region      types.String             `tfsdk:"region"`
+	Schema      types.String             `tfsdk:"schema"`
+	Tables      []branchSchemaTableModel `tfsdk:"tables"`
+}
+
+// branchSchemaTableModel maps tables schema data.
+type branchSchemaTableModel struct {
+	Name    types.String              `tfsdk:"name"`
+	Columns []branchSchemaColumnModel `tfsdk:"columns"`
+}
+
+// branchSchemaColumnModel maps columns schema data.
+type branchSchemaColumnModel struct {
+	Name         types.String `tfsdk:"name"`
+	Type         types.String `tfsdk:"type"`
+	NotNull      types.Bool   `tfsdk:"not_null"`
+	Unique       types.Bool   `tfsdk:"unique"`
+	Link         types.String `tfsdk:"link"`
+	DefaultValue types.String `tfsdk:"default_value"`
+}
+
+// branchSchemaDataSource is the data source implementation.
+type branchSchemaDataSource struct {
+	client *xataClient
+}
+
+// NewBranchSchemaDataSource is a helper function to simplify the provider implementation.
+func NewBranchSchemaDataSource() datasource.DataSource {
+	return &branchSchemaDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *branchSchemaDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branch_schema"
+}
+
+// Schema defines the schema for the data source.
+func (d *branchSchemaDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the live schema of a database branch, both as nested attributes and as a JSON document.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the branch schema in the form workspace_id/database:branch.",
+				Computed:    true,
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace the database belongs to. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"database": schema.StringAttribute{
+				Description: "Name of the database the branch belongs to. Defaults to the database of the provider.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Name of the branch. Defaults to the branch of the provider, main when unset.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database of the branch is hosted.",
+				Computed:    true,
+			},
+			"schema": schema.StringAttribute{
+				Description: "Branch schema as a canonical JSON document, with tables and columns sorted by name and " +
+					"internal Xata columns removed, in the format accepted by the xata_branch_schema resource.",
+				Computed: true,
+			},
+			"tables": schema.ListNestedAttribute{
+				Description: "Tables of the branch, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of each table.",
+							Computed:    true,
+						},
+						"columns": schema.ListNestedAttribute{
+							Description: "Columns of each table, sorted by name. Columns of object columns follow " +
+								"their parent and are named parent.column.",
+							Computed: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Description: "Name of each column.",
+										Computed:    true,
+									},
+									"type": schema.StringAttribute{
+										Description: "Type of each column.",
+										Computed:    true,
+									},
+									"not_null": schema.BoolAttribute{
+										Description: "Whether each column rejects null values.",
+										Computed:    true,
+									},
+									"unique": schema.BoolAttribute{
+										Description: "Whether the values of each column are unique.",
+										Computed:    true,
+									},
+									"link": schema.StringAttribute{
+										Description: "Table each link column points to.",
+										Computed:    true,
+									},
+									"default_value": schema.StringAttribute{
+										Description: "Default value of each column, if any.",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *branchSchemaDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *branchSchemaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state branchSchemaDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applyDataSourceDefault(d.client, "workspace_id", &state.WorkspaceId, &resp.Diagnostics)
+	applyDataSourceDefault(d.client, "database", &state.Database, &resp.Diagnostics)
+	applyDataSourceDefault(d.client, "branch", &state.Branch, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	workspaceID, database, branch := state.WorkspaceId.ValueString(), state.Database.ValueString(), state.Branch.ValueString()
+
+	region, found, err := d.client.databaseRegion(ctx, workspaceID, database)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Database",
+			fmt.Sprintf("Could not look database %q up, unexpected error: %s", database, err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Xata database not found",
+			fmt.Sprintf("Database %q does not exist in workspace %q.", database, workspaceID),
+		)
+		return
+	}
+	state.Region = types.StringValue(region)
+
+	// Get live branch schema
+	workspaceURL := d.client.workspaceURL(workspaceID, region)
+	live, err := d.client.api.GetBranchSchema(ctx, workspaceURL, database+":"+branch)
+	if isNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("branch"),
+			"Xata branch not found",
+			fmt.Sprintf("Branch %q does not exist in database %q.", branch, database),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Branch Schema",
+			fmt.Sprintf("Could not read branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	document, err := marshalBranchSchema(live)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Branch Schema",
+			fmt.Sprintf("Could not encode branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Map response body to model
+	state.Id = types.StringValue(fmt.Sprintf("%s/%s:%s", workspaceID, database, branch))
+	state.Schema = types.StringValue(document)
+	state.Tables = []branchSchemaTableModel{}
+	for _, table := range canonicalBranchSchema(live).Tables {
+		state.Tables = append(state.Tables, branchSchemaTableModel{
+			Name:    types.StringValue(table.Name),
+			Columns: newBranchSchemaColumnModels("", table.Columns),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// newBranchSchemaColumnModels maps canonical schema columns to their schema
+// data, flattening the columns of object columns after their parent with
+// names prefixed by the parent name.
+func newBranchSchemaColumnModels(prefix string, columns []schemaColumn) []branchSchemaColumnModel {
+	models := []branchSchemaColumnModel{}
+	for _, column := range columns {
+		model := branchSchemaColumnModel{
+			Name:         types.StringValue(prefix + column.Name),
+			Type:         types.StringValue(column.Type),
+			NotNull:      types.BoolValue(column.NotNull != nil && *column.NotNull),
+			Unique:       types.BoolValue(column.Unique != nil && *column.Unique),
+			Link:         types.StringNull(),
+			DefaultValue: types.StringPointerValue(column.DefaultValue),
+		}
+		if column.Link != nil {
+			model.Link = types.StringValue(column.Link.Table)
+		}
+
+		models = append(models, model)
+		models = append(models, newBranchSchemaColumnModels(prefix+column.Name+".", column.Columns)...)
+	}
+
+	return models
+}
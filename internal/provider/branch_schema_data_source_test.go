@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBranchSchemaDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = xata_database.inventory.default_branch
+  schema = jsonencode({
+    tables = [
+      {
+        name = "suppliers"
+        columns = [
+          { name = "name", type = "string", unique = true },
+        ]
+      },
+      {
+        name = "items"
+        columns = [
+          { name = "title", type = "string", notNull = true, defaultValue = "untitled" },
+          { name = "supplier", type = "link", link = { table = "suppliers" } },
+        ]
+      },
+    ]
+  })
+}
+
+data "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+
+  depends_on = [xata_branch_schema.main]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "branch", "main"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "region", "us-east-1"),
+					resource.TestCheckResourceAttrPair("data.xata_branch_schema.main", "id", "xata_branch_schema.main", "id"),
+					resource.TestCheckResourceAttrSet("data.xata_branch_schema.main", "schema"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.name", "items"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.0.name", "supplier"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.0.type", "link"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.0.link", "suppliers"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.1.name", "title"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.1.not_null", "true"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.1.unique", "false"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.0.columns.1.default_value", "untitled"),
+					resource.TestCheckResourceAttr("data.xata_branch_schema.main", "tables.1.columns.0.unique", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestNewBranchSchemaColumnModels(t *testing.T) {
+	notNull := true
+	columns := []schemaColumn{
+		{Name: "address", Type: "object", Columns: []schemaColumn{
+			{Name: "city", Type: "string", NotNull: &notNull},
+		}},
+		{Name: "owner", Type: "link", Link: &schemaColumnLink{Table: "users"}},
+	}
+
+	want := []branchSchemaColumnModel{
+		{
+			Name: types.StringValue("address"), Type: types.StringValue("object"),
+			NotNull: types.BoolValue(false), Unique: types.BoolValue(false),
+			Link: types.StringNull(), DefaultValue: types.StringNull(),
+		},
+		{
+			Name: types.StringValue("address.city"), Type: types.StringValue("string"),
+			NotNull: types.BoolValue(true), Unique: types.BoolValue(false),
+			Link: types.StringNull(), DefaultValue: types.StringNull(),
+		},
+		{
+			Name: types.StringValue("owner"), Type: types.StringValue("link"),
+			NotNull: types.BoolValue(false), Unique: types.BoolValue(false),
+			Link: types.StringValue("users"), DefaultValue: types.StringNull(),
+		},
+	}
+
+	if got := newBranchSchemaColumnModels("", columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("newBranchSchemaColumnModels() = %v, want %v", got, want)
+	}
+}
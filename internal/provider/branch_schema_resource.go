@@ -0,0 +1,476 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &branchSchemaResource{}
+	_ resource.ResourceWithConfigure      = &branchSchemaResource{}
+	_ resource.ResourceWithImportState    = &branchSchemaResource{}
+	_ resource.ResourceWithModifyPlan     = &branchSchemaResource{}
+	_ resource.ResourceWithValidateConfig = &branchSchemaResource{}
+)
+
+// NewBranchSchemaResource is a helper function to simplify the provider implementation.
+func NewBranchSchemaResource() resource.Resource {
+	return &branchSchemaResource{}
+}
+
+// branchSchemaResource is the resource implementation.
+type branchSchemaResource struct {
+	client *xataClient
+}
+
+// branchSchemaResourceModel maps the resource schema data.
+type branchSchemaResourceModel struct {
+	Id          types.String      `tfsdk:"id"`
+	WorkspaceId types.String      `tfsdk:"workspace_id"`
+	Database    types.String      `tfsdk:"database"`
+	Branch      types.String      `tfsdk:"branch"`
+	Schema      branchSchemaValue `tfsdk:"schema"`
+	Region      types.String      `tfsdk:"region"`
+	Changes     types.List        `tfsdk:"changes"`
+	LastUpdated types.String      `tfsdk:"last_updated"`
+	Timeouts    timeouts.Value    `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *branchSchemaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branch_schema"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *branchSchemaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *branchSchemaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the whole schema of a database branch from a single JSON document. " +
+			"Destroying the resource leaves the branch schema untouched. " +
+			"Branches of databases with Postgres enabled are not supported, as their schema can only be changed through pgroll migrations.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the branch schema in the form workspace_id/database:branch.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace the database belongs to. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Description: "Name of the database the branch belongs to. Defaults to the database of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Name of the branch whose schema is managed. Defaults to the branch of the provider, main when unset.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Branch schema as a JSON document with a tables list, in the format written by `xata schema dump`. " +
+					"Documents describing the same schema, whatever their formatting and order, are equivalent.",
+				CustomType: branchSchemaType{},
+				Required:   true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database of the branch is hosted.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"changes": schema.ListAttribute{
+				Description: "Operations applied by the last create or update of the branch schema, empty when imported. " +
+					"The plan shows the operations about to be applied.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the branch schema.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that the schema document is well formed.
+func (r *branchSchemaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var document branchSchemaValue
+	diags := req.Config.GetAttribute(ctx, path.Root("schema"), &document)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || document.IsNull() || document.IsUnknown() {
+		return
+	}
+
+	if _, err := parseBranchSchema(document.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Invalid Branch Schema",
+			fmt.Sprintf("The schema document is not a valid Xata branch schema: %s", err.Error()),
+		)
+	}
+}
+
+// ModifyPlan sets the attributes left unset to the provider defaults and
+// computes the schema operations shown in the plan.
+func (r *branchSchemaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id", "database", "branch")
+
+	// Nothing to compare on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state branchSchemaResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.Schema.IsUnknown() {
+		return
+	}
+
+	current, err := parseBranchSchema(state.Schema.ValueString())
+	if err != nil {
+		return
+	}
+	desired, err := parseBranchSchema(plan.Schema.ValueString())
+	if err != nil {
+		return
+	}
+
+	changes := diffBranchSchemas(current, desired)
+	if len(changes) == 0 {
+		plan.Changes = state.Changes
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("changes"), plan.Changes)...)
+		return
+	}
+
+	if !plan.WorkspaceId.IsUnknown() && !plan.Database.IsUnknown() {
+		postgresEnabled, err := r.postgresEnabled(ctx, plan.WorkspaceId.ValueString(), plan.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error planning Xata branch schema",
+				fmt.Sprintf("Could not read database %q, unexpected error: %s", plan.Database.ValueString(), err.Error()),
+			)
+			return
+		}
+		if postgresEnabled {
+			resp.Diagnostics.AddAttributeError(path.Root("database"), "Unsupported Xata Database", postgresEnabledDetail(plan.Database.ValueString()))
+			return
+		}
+	}
+
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, change.Description)
+	}
+	changeList, diags := types.ListValueFrom(ctx, types.StringType, descriptions)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("changes"), changeList)...)
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("schema"),
+		"Branch Schema Changes",
+		fmt.Sprintf("The schema of branch %s:%s will change:\n  - %s",
+			plan.Database.ValueString(), plan.Branch.ValueString(), strings.Join(descriptions, "\n  - ")),
+	)
+}
+
+// Create a new resource.
+func (r *branchSchemaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan branchSchemaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resolve the workspace API serving the database
+	region, found, err := r.client.databaseRegion(ctx, plan.WorkspaceId.ValueString(), plan.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata branch schema",
+			fmt.Sprintf("Could not read database %q, unexpected error: %s", plan.Database.ValueString(), err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Error creating Xata branch schema",
+			fmt.Sprintf("Database %q does not exist in workspace %q.", plan.Database.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.Region = types.StringValue(region)
+
+	// Apply the schema
+	changes, err := r.applySchema(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata branch schema",
+			fmt.Sprintf("Could not update branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	changeList, diags := types.ListValueFrom(ctx, types.StringType, changes)
+	resp.Diagnostics.Append(diags...)
+	plan.Changes = changeList
+	plan.Id = types.StringValue(fmt.Sprintf("%s/%s:%s", plan.WorkspaceId.ValueString(), plan.Database.ValueString(), plan.Branch.ValueString()))
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *branchSchemaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state branchSchemaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported branch schemas do not know their region yet
+	if state.Region.IsNull() || state.Region.ValueString() == "" {
+		region, found, err := r.client.databaseRegion(ctx, state.WorkspaceId.ValueString(), state.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Xata branch schema",
+				fmt.Sprintf("Could not read database %q, unexpected error: %s", state.Database.ValueString(), err.Error()),
+			)
+			return
+		}
+		if !found {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		state.Region = types.StringValue(region)
+	}
+
+	// Get live branch schema
+	workspaceURL := r.client.workspaceURL(state.WorkspaceId.ValueString(), state.Region.ValueString())
+	live, err := r.client.api.GetBranchSchema(ctx, workspaceURL, state.Database.ValueString()+":"+state.Branch.ValueString())
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata branch schema",
+			fmt.Sprintf("Could not read branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// The configured document is kept when it describes the live schema, as
+	// both are semantically equal
+	document, err := marshalBranchSchema(live)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata branch schema",
+			fmt.Sprintf("Could not encode branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	state.Schema = newBranchSchemaValue(document)
+	if state.Changes.IsNull() {
+		state.Changes = types.ListValueMust(types.StringType, nil)
+	}
+	state.Id = types.StringValue(fmt.Sprintf("%s/%s:%s", state.WorkspaceId.ValueString(), state.Database.ValueString(), state.Branch.ValueString()))
+
+	// Return branch schema info
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *branchSchemaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan branchSchemaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Apply the schema
+	changes, err := r.applySchema(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Xata Branch Schema",
+			fmt.Sprintf("Could not update branch schema, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// The planned changes were computed against the refreshed state, keep
+	// them unless they could not be known during plan.
+	if plan.Changes.IsUnknown() {
+		changeList, diags := types.ListValueFrom(ctx, types.StringType, changes)
+		resp.Diagnostics.Append(diags...)
+		plan.Changes = changeList
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete removes the branch schema from the Terraform state only, the
+// tables of the branch are left in place.
+func (r *branchSchemaResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+func (r *branchSchemaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID, database name and branch name
+	workspaceID, dbBranchName, _ := strings.Cut(req.ID, "/")
+	database, branch, _ := strings.Cut(dbBranchName, ":")
+	if workspaceID == "" || database == "" || branch == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/database:branch. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("branch"), branch)...)
+}
+
+// applySchema migrates the live branch schema to the schema in model and
+// returns the descriptions of the applied operations.
+func (r *branchSchemaResource) applySchema(ctx context.Context, model branchSchemaResourceModel) ([]string, error) {
+	desired, err := parseBranchSchema(model.Schema.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceURL := r.client.workspaceURL(model.WorkspaceId.ValueString(), model.Region.ValueString())
+	dbBranchName := model.Database.ValueString() + ":" + model.Branch.ValueString()
+	live, err := r.client.api.GetBranchSchema(ctx, workspaceURL, dbBranchName)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffBranchSchemas(live, desired)
+	descriptions := make([]string, 0, len(changes))
+	if len(changes) == 0 {
+		return descriptions, nil
+	}
+
+	postgresEnabled, err := r.postgresEnabled(ctx, model.WorkspaceId.ValueString(), model.Database.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	if postgresEnabled {
+		return nil, errors.New(postgresEnabledDetail(model.Database.ValueString()))
+	}
+
+	operations := make([]migrationOperation, 0, len(changes))
+	for _, change := range changes {
+		operations = append(operations, change.Operation)
+		descriptions = append(descriptions, change.Description)
+	}
+
+	err = r.client.api.UpdateBranchSchema(ctx, workspaceURL, dbBranchName, updateBranchSchemaRequest{
+		Operations: operations,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return descriptions, nil
+}
+
+// postgresEnabled reports whether the database has Postgres enabled. The
+// schema of its branches can only be changed through pgroll migrations,
+// which the schema/update endpoint used by this resource does not run.
+func (r *branchSchemaResource) postgresEnabled(ctx context.Context, workspaceID string, database string) (bool, error) {
+	details, err := r.client.api.GetDatabaseMetadata(ctx, workspaceID, database)
+	if err != nil {
+		return false, err
+	}
+
+	return details.PostgresEnabled, nil
+}
+
+// postgresEnabledDetail describes why the schema of a branch of a database
+// with Postgres enabled cannot be managed.
+func postgresEnabledDetail(database string) string {
+	return fmt.Sprintf("Database %q has Postgres enabled. The schema of its branches can only be changed through pgroll migrations, "+
+		"which xata_branch_schema does not run. Manage its tables with xata_table instead.", database)
+}
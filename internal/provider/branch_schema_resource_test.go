@@ -0,0 +1,288 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBranchSchemaResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = xata_database.inventory.default_branch
+  schema = jsonencode({
+    tables = [
+      {
+        name = "suppliers"
+        columns = [
+          { name = "name", type = "string", unique = true },
+        ]
+      },
+      {
+        name = "items"
+        columns = [
+          { name = "title", type = "string", notNull = true, defaultValue = "untitled" },
+          { name = "supplier", type = "link", link = { table = "suppliers" } },
+        ]
+      },
+    ]
+  })
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "branch", "main"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "region", "us-east-1"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.#", "5"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.0", "add table items"),
+					resource.TestCheckResourceAttrSet("xata_branch_schema.main", "id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xata_branch_schema.main",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The imported schema is the canonical form of the live
+				// schema, and changes and last_updated only exist in
+				// Terraform.
+				ImportStateVerifyIgnore: []string{"schema", "changes", "last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = xata_database.inventory.default_branch
+  schema = jsonencode({
+    tables = [
+      {
+        name = "items"
+        columns = [
+          { name = "title", type = "text", notNull = true, defaultValue = "untitled" },
+          { name = "price", type = "float" },
+        ]
+      },
+    ]
+  })
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.#", "5"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.0", "remove column items.title (definition changed)"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.4", "remove table suppliers"),
+				),
+			},
+			// The same schema written differently applies no operation and
+			// keeps the changes of the last apply
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  branch       = xata_database.inventory.default_branch
+  schema       = <<-EOT
+    {
+      "tables": [
+        {
+          "columns": [
+            { "type": "float", "name": "price", "unique": false },
+            { "defaultValue": "untitled", "notNull": true, "name": "title", "type": "text" }
+          ],
+          "name": "items"
+        }
+      ]
+    }
+  EOT
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.#", "5"),
+					resource.TestCheckResourceAttr("xata_branch_schema.main", "changes.0", "remove column items.title (definition changed)"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBranchSchemaResource_postgresEnabled(t *testing.T) {
+	// Databases cannot be created with Postgres enabled through the
+	// provider, the mock Xata API enables it directly.
+	if testAccMock == nil {
+		t.Skip("databases with Postgres enabled are only tested against the mock Xata API")
+	}
+
+	config := providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "ledger" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "ledger"
+  region       = "us-east-1"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					if err := testAccMock.enablePostgres("ledger"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config + `
+resource "xata_branch_schema" "main" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.ledger.name
+  branch       = xata_database.ledger.default_branch
+  schema = jsonencode({
+    tables = [
+      {
+        name    = "entries"
+        columns = [{ name = "amount", type = "float" }]
+      },
+    ]
+  })
+}
+`,
+				ExpectError: regexp.MustCompile(`Database "ledger" has Postgres enabled`),
+			},
+		},
+	})
+}
+
+func TestBranchSchemaValueSemanticEquals(t *testing.T) {
+	prior := newBranchSchemaValue(`{"tables":[{"name":"items","columns":[{"name":"title","type":"string","notNull":true},{"name":"price","type":"float"}]}]}`)
+
+	tests := map[string]struct {
+		document string
+		want     bool
+	}{
+		"formatting and order": {
+			document: `{
+  "tables": [
+    {"columns": [{"type": "float", "name": "price", "unique": false}, {"notNull": true, "name": "title", "type": "string"}], "name": "items"}
+  ]
+}`,
+			want: true,
+		},
+		"changed column": {
+			document: `{"tables":[{"name":"items","columns":[{"name":"title","type":"text","notNull":true},{"name":"price","type":"float"}]}]}`,
+			want:     false,
+		},
+		"invalid document": {
+			document: `{"tables":`,
+			want:     false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := prior.StringSemanticEquals(context.Background(), newBranchSchemaValue(test.document))
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if got != test.want {
+				t.Errorf("StringSemanticEquals() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiffBranchSchemas(t *testing.T) {
+	current, err := parseBranchSchema(`{
+  "tables": [
+    {"name": "items", "columns": [
+      {"name": "title", "type": "string", "notNull": false},
+      {"name": "legacy", "type": "int"}
+    ]},
+    {"name": "archive", "columns": []}
+  ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := parseBranchSchema(`{
+  "tables": [
+    {"name": "items", "columns": [
+      {"name": "title", "type": "text"},
+      {"name": "owner", "type": "link", "link": {"table": "users"}}
+    ]},
+    {"name": "users", "columns": [{"name": "email", "type": "email", "unique": true}]}
+  ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var descriptions []string
+	for _, change := range diffBranchSchemas(current, desired) {
+		descriptions = append(descriptions, change.Description)
+	}
+
+	want := []string{
+		"add table users",
+		"remove column items.title (definition changed)",
+		"remove column items.legacy",
+		"add column items.owner (link)",
+		"add column items.title (text)",
+		"add column users.email (email)",
+		"remove table archive",
+	}
+	if !reflect.DeepEqual(descriptions, want) {
+		t.Errorf("unexpected changes:\ngot  %q\nwant %q", descriptions, want)
+	}
+
+	if changes := diffBranchSchemas(current, current); len(changes) != 0 {
+		t.Errorf("expected no changes between identical schemas, got %d", len(changes))
+	}
+}
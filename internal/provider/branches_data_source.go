@@ -0,0 +1,262 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &branchesDataSource{}
+	_ datasource.DataSourceWithConfigure = &branchesDataSource{}
+)
+
+// branchesDataSourceModel maps the data source schema data.
+type branchesDataSourceModel struct {
+	WorkspaceId types.String    `tfsdk:"workspace_id"`
+	Database    types.String    `tfsdk:"database"`
+	NamePrefix  types.String    `tfsdk:"name_prefix"`
+	Label       types.String    `tfsdk:"label"`
+	Region      types.String    `tfsdk:"region"`
+	Branches    []branchesModel `tfsdk:"branches"`
+	Names       []types.String  `tfsdk:"names"`
+}
+
+// branchesModel maps branches schema data.
+type branchesModel struct {
+	Name      types.String         `tfsdk:"name"`
+	CreatedAt types.String         `tfsdk:"created_at"`
+	Parent    types.String         `tfsdk:"parent"`
+	Metadata  *branchMetadataModel `tfsdk:"metadata"`
+}
+
+// branchesDataSource is the data source implementation.
+type branchesDataSource struct {
+	client *xataClient
+}
+
+// NewBranchesDataSource is a helper function to simplify the provider implementation.
+func NewBranchesDataSource() datasource.DataSource {
+	return &branchesDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *branchesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branches"
+}
+
+// Schema defines the schema for the data source.
+func (d *branchesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of branches of a database, optionally filtered, sorted by name.",
+		Attributes: map[string]schema.Attribute{
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace. Defaults to the provider workspace_id.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"database": schema.StringAttribute{
+				Description: "Name of the database. Defaults to the provider database.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name_prefix": schema.StringAttribute{
+				Description: "Prefix the name of the branches must start with.",
+				Optional:    true,
+			},
+			"label": schema.StringAttribute{
+				Description: "Label the metadata of the branches must contain.",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database is hosted.",
+				Computed:    true,
+			},
+			"names": schema.ListAttribute{
+				Description: "Names of the branches, in the order of branches.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"branches": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of each branch.",
+							Computed:    true,
+						},
+						"created_at": schema.StringAttribute{
+							Description: "Creation timestamp of each branch.",
+							Computed:    true,
+						},
+						"parent": schema.StringAttribute{
+							Description: "Branch each branch was created from, if any.",
+							Computed:    true,
+						},
+						"metadata": schema.SingleNestedAttribute{
+							Description: "Git metadata attached to each branch, if any.",
+							Computed:    true,
+							Attributes: map[string]schema.Attribute{
+								"repository": schema.StringAttribute{
+									Description: "Repository the branch is associated with.",
+									Computed:    true,
+								},
+								"branch": schema.StringAttribute{
+									Description: "Git branch the branch is associated with.",
+									Computed:    true,
+								},
+								"stage": schema.StringAttribute{
+									Description: "Deployment stage of the branch.",
+									Computed:    true,
+								},
+								"labels": schema.ListAttribute{
+									Description: "Labels attached to the branch.",
+									ElementType: types.StringType,
+									Computed:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *branchesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *branchesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state branchesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applyDataSourceDefault(d.client, "workspace_id", &state.WorkspaceId, &resp.Diagnostics)
+	applyDataSourceDefault(d.client, "database", &state.Database, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	workspaceID, database := state.WorkspaceId.ValueString(), state.Database.ValueString()
+
+	region, found, err := d.client.databaseRegion(ctx, workspaceID, database)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Database",
+			fmt.Sprintf("Could not look database %q up, unexpected error: %s", database, err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Xata database not found",
+			fmt.Sprintf("Database %q does not exist in workspace %q.", database, workspaceID),
+		)
+		return
+	}
+	state.Region = types.StringValue(region)
+
+	branchClient, ok := workspaceClient[xata.BranchClient](d.client, workspaceID, region, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	branchList, err := branchClient.List(ctx, database)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Branches",
+			fmt.Sprintf("Could not list the branches of database %q, unexpected error: %s", database, err.Error()),
+		)
+		return
+	}
+
+	// Map response body to model, keeping the branches matching the name
+	// prefix before fetching their details
+	for _, branch := range branchList.Branches {
+		if !strings.HasPrefix(branch.Name, state.NamePrefix.ValueString()) {
+			continue
+		}
+		state.Branches = append(state.Branches, branchesModel{
+			Name:      types.StringValue(branch.Name),
+			CreatedAt: types.StringValue(branch.CreatedAt.Format(time.RFC3339)),
+			Parent:    types.StringNull(),
+		})
+	}
+
+	// The list omits the parent and metadata of the branches, fetch them
+	// concurrently before filtering by label
+	err = forEachConcurrently(ctx, d.client.concurrency(), len(state.Branches), func(ctx context.Context, i int) error {
+		branch := &state.Branches[i]
+		details, err := branchClient.GetDetails(ctx, xata.BranchRequest{
+			DatabaseName: xata.String(database),
+			BranchName:   branch.Name.ValueString(),
+		})
+		if err != nil {
+			return fmt.Errorf("branch %s: %w", branch.Name.ValueString(), err)
+		}
+
+		if details.StartedFrom != nil {
+			branch.Parent = stringValueOrNull(details.StartedFrom.BranchName)
+		}
+		if details.Metadata != nil {
+			branch.Metadata = newBranchMetadataModel(branchMetadata{
+				Repository: details.Metadata.Repository,
+				Branch:     details.Metadata.Branch,
+				Stage:      details.Metadata.Stage,
+				Labels:     details.Metadata.Labels,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Branch Details",
+			err.Error(),
+		)
+		return
+	}
+
+	if !state.Label.IsNull() {
+		label := state.Label.ValueString()
+		state.Branches = slices.DeleteFunc(state.Branches, func(branch branchesModel) bool {
+			return branch.Metadata == nil || !slices.ContainsFunc(branch.Metadata.Labels, func(candidate types.String) bool {
+				return candidate.ValueString() == label
+			})
+		})
+	}
+
+	sort.SliceStable(state.Branches, func(i, j int) bool {
+		return state.Branches[i].Name.ValueString() < state.Branches[j].Name.ValueString()
+	})
+
+	state.Names = make([]types.String, 0, len(state.Branches))
+	for _, branch := range state.Branches {
+		state.Names = append(state.Names, branch.Name)
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBranchesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_branch" "pr_42" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "pr-42"
+  from         = xata_database.inventory.default_branch
+
+  metadata = {
+    repository = "github.com/markspace/inventory"
+    branch     = "feature/pr-42"
+    stage      = "preview"
+    labels     = ["preview", "stale"]
+  }
+}
+
+resource "xata_branch" "pr_43" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "pr-43"
+  from         = xata_database.inventory.default_branch
+
+  metadata = {
+    labels = ["preview"]
+  }
+}
+
+data "xata_branches" "all" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+
+  depends_on = [xata_branch.pr_42, xata_branch.pr_43]
+}
+
+data "xata_branches" "previews" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name_prefix  = "pr-"
+
+  depends_on = [xata_branch.pr_42, xata_branch.pr_43]
+}
+
+data "xata_branches" "stale" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  label        = "stale"
+
+  depends_on = [xata_branch.pr_42, xata_branch.pr_43]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xata_branches.all", "region", "us-east-1"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.#", "3"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.0.name", "main"),
+					resource.TestCheckNoResourceAttr("data.xata_branches.all", "branches.0.parent"),
+					resource.TestCheckNoResourceAttr("data.xata_branches.all", "branches.0.metadata"),
+					resource.TestCheckResourceAttrSet("data.xata_branches.all", "branches.0.created_at"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.name", "pr-42"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.parent", "main"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.metadata.repository", "github.com/markspace/inventory"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.metadata.branch", "feature/pr-42"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.metadata.stage", "preview"),
+					resource.TestCheckResourceAttr("data.xata_branches.all", "branches.1.metadata.labels.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_branches.previews", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_branches.previews", "names.0", "pr-42"),
+					resource.TestCheckResourceAttr("data.xata_branches.previews", "names.1", "pr-43"),
+					resource.TestCheckResourceAttr("data.xata_branches.stale", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.xata_branches.stale", "branches.0.name", "pr-42"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,435 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultTimeout bounds resource operations without a timeout configured in
+// their timeouts block.
+const defaultTimeout = 20 * time.Minute
+
+// defaultConcurrency bounds the concurrent calls of data sources fetching
+// the details of every listed item, unless max_concurrent_requests is set.
+const defaultConcurrency = 8
+
+// xataClient is the bundle of Xata API clients shared by resources and data
+// sources. Control plane clients are built once by the provider, clients of
+// the workspace API are built on first use for each workspace endpoint.
+type xataClient struct {
+	apikey     string
+	baseURL    string
+	region     string
+	httpClient *http.Client
+
+	// workspaceID, database and branch are the defaults of resources which
+	// do not set them.
+	workspaceID string
+	database    string
+	branch      string
+
+	// maxConcurrentRequests is the max_concurrent_requests provider
+	// setting, 0 when unset.
+	maxConcurrentRequests int
+
+	workspaces xata.WorkspacesClient
+	databases  xata.DatabasesClient
+	users      xata.UsersClient
+	api        *apiClient
+
+	mu        sync.Mutex
+	endpoints map[string]*workspaceClients
+}
+
+// workspaceClients holds the Xata API clients bound to the endpoint of a
+// workspace in a region.
+type workspaceClients struct {
+	branches xata.BranchClient
+	tables   xata.TableClient
+	records  xata.RecordsClient
+	search   xata.SearchAndFilterClient
+	files    xata.FilesClient
+}
+
+// newXataClient builds the client bundle for the given API key and
+// endpoints.
+func newXataClient(apikey string, controlPlaneURL string, workspaceURL string, region string, httpClient *http.Client) (*xataClient, error) {
+	options := []xata.ClientOption{
+		xata.WithAPIKey(apikey),
+		xata.WithHTTPClient(httpClient),
+		xata.WithBaseURL(controlPlaneURL),
+	}
+
+	workspaces, err := xata.NewWorkspacesClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	databases, err := xata.NewDatabasesClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	users, err := xata.NewUsersClient(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xataClient{
+		apikey:     apikey,
+		baseURL:    workspaceURL,
+		region:     region,
+		httpClient: httpClient,
+		workspaces: workspaces,
+		databases:  databases,
+		users:      users,
+		api: &apiClient{
+			httpClient:      httpClient,
+			controlPlaneURL: controlPlaneURL,
+			apikey:          apikey,
+		},
+		endpoints: map[string]*workspaceClients{},
+	}, nil
+}
+
+// providerClient returns the client bundle passed by the provider to the
+// Configure method of resources and data sources. It returns nil without
+// diagnostics when the provider has not been configured yet.
+func providerClient(providerData any, diags *diag.Diagnostics) *xataClient {
+	// Terraform sets the provider data after it calls the
+	// ConfigureProvider RPC.
+	if providerData == nil {
+		return nil
+	}
+
+	client, ok := providerData.(*xataClient)
+	if !ok {
+		diags.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *xataClient, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil
+	}
+
+	return client
+}
+
+// workspaceClient returns the Xata API client of type T, one of the
+// workspaceClients fields, bound to a workspace in a region. Failures are
+// reported as diagnostics.
+func workspaceClient[T any](c *xataClient, workspaceID string, region string, diags *diag.Diagnostics) (T, bool) {
+	var zero T
+
+	if c == nil {
+		diags.AddError(
+			"Unconfigured Xata API Client",
+			"The Xata API client is not configured. Please report this issue to the provider developers.",
+		)
+		return zero, false
+	}
+
+	clients, err := c.workspaceClients(workspaceID, region)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Xata API Client",
+			fmt.Sprintf("An unexpected error occurred when creating the Xata API client for workspace %q.\n\n"+
+				"Xata Client Error: %s", workspaceID, err.Error()),
+		)
+		return zero, false
+	}
+
+	for _, candidate := range []any{clients.branches, clients.tables, clients.records, clients.search, clients.files} {
+		if client, ok := candidate.(T); ok {
+			return client, true
+		}
+	}
+
+	diags.AddError(
+		"Unexpected Xata API Client Type",
+		fmt.Sprintf("No Xata API client of type %T. Please report this issue to the provider developers.", zero),
+	)
+	return zero, false
+}
+
+// workspaceClients returns the clients bound to a workspace in a region,
+// building them on first use.
+func (c *xataClient) workspaceClients(workspaceID string, region string) (*workspaceClients, error) {
+	endpoint := c.workspaceURL(workspaceID, region)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if clients, ok := c.endpoints[endpoint]; ok {
+		return clients, nil
+	}
+
+	options := []xata.ClientOption{
+		xata.WithAPIKey(c.apikey),
+		xata.WithHTTPClient(c.httpClient),
+		xata.WithBaseURL(endpoint),
+	}
+
+	var (
+		clients workspaceClients
+		err     error
+	)
+	if clients.branches, err = xata.NewBranchClient(options...); err != nil {
+		return nil, err
+	}
+	if clients.tables, err = xata.NewTableClient(options...); err != nil {
+		return nil, err
+	}
+	if clients.records, err = xata.NewRecordsClient(options...); err != nil {
+		return nil, err
+	}
+	if clients.search, err = xata.NewSearchAndFilterClient(options...); err != nil {
+		return nil, err
+	}
+	if clients.files, err = xata.NewFilesClient(options...); err != nil {
+		return nil, err
+	}
+
+	c.endpoints[endpoint] = &clients
+	return &clients, nil
+}
+
+// concurrency returns the number of calls data sources run concurrently:
+// max_concurrent_requests when set, defaultConcurrency otherwise.
+func (c *xataClient) concurrency() int {
+	if c.maxConcurrentRequests > 0 {
+		return c.maxConcurrentRequests
+	}
+	return defaultConcurrency
+}
+
+// forEachConcurrently calls fn for every index below n, running at most
+// limit calls at a time, and returns the first error, cancelling the context
+// of the remaining calls.
+func forEachConcurrently(ctx context.Context, limit int, n int, fn func(ctx context.Context, i int) error) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(limit)
+	for i := range n {
+		group.Go(func() error {
+			return fn(groupCtx, i)
+		})
+	}
+	return group.Wait()
+}
+
+// timeoutContext returns a context bounded by the timeout configured for an
+// operation in the timeouts block of a resource, defaultTimeout when none is
+// configured. The returned cancel function must always be called.
+func timeoutContext(ctx context.Context, timeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	duration, timeoutDiags := timeout(ctx, defaultTimeout)
+	diags.Append(timeoutDiags...)
+	if timeoutDiags.HasError() {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, duration)
+}
+
+// workspaceURL returns the base URL of the workspace API serving the
+// databases of a workspace in the given region, falling back to the
+// provider region when none is given.
+func (c *xataClient) workspaceURL(workspaceID string, region string) string {
+	if region == "" {
+		region = c.region
+	}
+	return strings.NewReplacer(
+		workspaceIDPlaceholder, workspaceID,
+		regionPlaceholder, region,
+	).Replace(c.baseURL)
+}
+
+// defaultBranch is the branch resources use when neither they nor the
+// provider set one.
+const defaultBranch = "main"
+
+// providerDefault returns the provider default of a resource attribute, or
+// an empty string when the provider has none.
+func (c *xataClient) providerDefault(attribute string) string {
+	switch attribute {
+	case "workspace_id":
+		return c.workspaceID
+	case "database":
+		return c.database
+	case "branch":
+		return cmp.Or(c.branch, defaultBranch)
+	default:
+		return ""
+	}
+}
+
+// applyProviderDefaults sets the given attributes of a planned resource to
+// the provider defaults when they are not configured. Changing the default
+// of an existing resource replaces it, as changing the attribute would.
+func applyProviderDefaults(ctx context.Context, c *xataClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attributes ...string) {
+	// Nothing to default on destroy, nor before the provider is configured
+	if req.Plan.Raw.IsNull() || c == nil {
+		return
+	}
+
+	for _, attribute := range attributes {
+		attributePath := path.Root(attribute)
+
+		var config types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, attributePath, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !config.IsNull() {
+			continue
+		}
+
+		value := c.providerDefault(attribute)
+		if value == "" {
+			resp.Diagnostics.AddAttributeError(
+				attributePath,
+				"Missing "+attribute,
+				fmt.Sprintf("The %[1]s attribute is required as the provider sets no default. "+
+					"Set it on the resource, or set the %[1]s attribute of the provider.", attribute),
+			)
+			continue
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, attributePath, value)...)
+
+		if req.State.Raw.IsNull() {
+			continue
+		}
+		var state types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, attributePath, &state)...)
+		if state.ValueString() != value {
+			resp.RequiresReplace.Append(attributePath)
+		}
+	}
+}
+
+// applyDataSourceDefault sets an attribute of a data source configuration to
+// the provider default when it is not configured. A missing default is
+// reported as a diagnostic.
+func applyDataSourceDefault(c *xataClient, attribute string, value *types.String, diags *diag.Diagnostics) {
+	if !value.IsNull() {
+		return
+	}
+
+	providerDefault := c.providerDefault(attribute)
+	if providerDefault == "" {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Missing "+attribute,
+			fmt.Sprintf("The %[1]s attribute is required as the provider sets no default. "+
+				"Set it on the data source, or set the %[1]s attribute of the provider.", attribute),
+		)
+		return
+	}
+	*value = types.StringValue(providerDefault)
+}
+
+// databaseURL holds the parts of a Xata database URL.
+type databaseURL struct {
+	workspaceID string
+	region      string
+	database    string
+	branch      string
+}
+
+// parseDatabaseURL parses a Xata database URL such as
+// https://markspace-a1b2c3.eu-west-1.xata.sh/db/inventory:main, in which the
+// branch is optional.
+func parseDatabaseURL(raw string) (databaseURL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return databaseURL{}, err
+	}
+
+	labels := strings.Split(u.Hostname(), ".")
+	if len(labels) < 3 || labels[0] == "" || labels[1] == "" {
+		return databaseURL{}, fmt.Errorf("expected a host such as {workspace_id}.{region}.xata.sh, got %q", u.Host)
+	}
+
+	dbBranch, ok := strings.CutPrefix(strings.TrimSuffix(u.Path, "/"), "/db/")
+	if !ok || dbBranch == "" || strings.Contains(dbBranch, "/") {
+		return databaseURL{}, fmt.Errorf("expected a path such as /db/{database}:{branch}, got %q", u.Path)
+	}
+	database, branch, _ := strings.Cut(dbBranch, ":")
+	if database == "" {
+		return databaseURL{}, fmt.Errorf("missing database name in %q", u.Path)
+	}
+
+	return databaseURL{
+		workspaceID: labels[0],
+		region:      labels[1],
+		database:    database,
+		branch:      branch,
+	}, nil
+}
+
+// resolveEndpoints returns the control plane URL and the workspace endpoint
+// template to use for the given provider settings. A base URL without
+// placeholders serves every call, unless a control plane URL is set.
+func resolveEndpoints(baseURL string, controlPlaneURL string) (string, string, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	controlPlaneURL = strings.TrimSuffix(controlPlaneURL, "/")
+
+	if baseURL == "" {
+		baseURL = defaultWorkspaceURL
+	}
+	if controlPlaneURL == "" {
+		controlPlaneURL = defaultControlPlaneURL
+		if !strings.Contains(baseURL, workspaceIDPlaceholder) && !strings.Contains(baseURL, regionPlaceholder) {
+			controlPlaneURL = baseURL
+		}
+	}
+
+	sample := strings.NewReplacer(workspaceIDPlaceholder, "workspace", regionPlaceholder, "region").Replace(baseURL)
+	if err := validateEndpoint(sample); err != nil {
+		return "", "", fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if err := validateEndpoint(controlPlaneURL); err != nil {
+		return "", "", fmt.Errorf("invalid control plane URL %q: %w", controlPlaneURL, err)
+	}
+
+	return controlPlaneURL, baseURL, nil
+}
+
+func validateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("expected an absolute http or https URL")
+	}
+	return nil
+}
+
+// databaseRegion returns the region hosting a database. It reports false
+// if the database does not exist in the workspace.
+func (c *xataClient) databaseRegion(ctx context.Context, workspaceID string, dbName string) (string, bool, error) {
+	databaseList, err := c.databases.ListWithWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, database := range databaseList.Databases {
+		if database.Name == dbName {
+			return database.Region, true, nil
+		}
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultProfile is the profile the Xata CLI logs in with when none is given.
+const defaultProfile = "default"
+
+// xataProfile holds the settings of a profile of the Xata CLI credentials
+// file.
+type xataProfile struct {
+	apiKey      string
+	workspaceID string
+	region      string
+}
+
+// credentialsPath returns the path of the credentials file written by
+// `xata auth login`.
+func credentialsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "xata", "credentials"), nil
+}
+
+// readProfile reads a profile from the INI formatted credentials file of the
+// Xata CLI, for instance:
+//
+//	[default]
+//	apiKey=xau_...
+//	workspaceId=markspace-a1b2c3
+//	region=eu-west-1
+//
+// It reports false when the file or the profile does not exist.
+func readProfile(path string, name string) (xataProfile, bool, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return xataProfile{}, false, nil
+	}
+	if err != nil {
+		return xataProfile{}, false, err
+	}
+	defer file.Close()
+
+	var (
+		profile xataProfile
+		found   bool
+		section string
+	)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return xataProfile{}, false, fmt.Errorf("%s:%d: malformed section %q", path, lineNumber, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == name
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return xataProfile{}, false, fmt.Errorf("%s:%d: expected key=value, got %q", path, lineNumber, line)
+		}
+		if section != name {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "apiKey":
+			profile.apiKey = value
+		case "workspaceId":
+			profile.workspaceID = value
+		case "region":
+			profile.region = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return xataProfile{}, false, err
+	}
+
+	return profile, found, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const testCredentials = `; written by xata auth login
+[default]
+apiKey=xau_default
+
+[staging]
+apiKey = "xau_staging"
+workspaceId = markspace-a1b2c3
+region = eu-west-1
+`
+
+func TestReadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte(testCredentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		profile   string
+		want      xataProfile
+		wantFound bool
+	}{
+		{
+			name:      "default profile",
+			path:      path,
+			profile:   "default",
+			want:      xataProfile{apiKey: "xau_default"},
+			wantFound: true,
+		},
+		{
+			name:      "named profile",
+			path:      path,
+			profile:   "staging",
+			want:      xataProfile{apiKey: "xau_staging", workspaceID: "markspace-a1b2c3", region: "eu-west-1"},
+			wantFound: true,
+		},
+		{
+			name:    "missing profile",
+			path:    path,
+			profile: "production",
+		},
+		{
+			name:    "missing file",
+			path:    filepath.Join(t.TempDir(), "credentials"),
+			profile: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := readProfile(tt.path, tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || found != tt.wantFound {
+				t.Fatalf("readProfile() = %+v, %v, want %+v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+
+	malformed := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(malformed, []byte("[default\napiKey=xau_default\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readProfile(malformed, "default"); err == nil {
+		t.Fatal("readProfile() accepted a malformed section")
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var diags diag.Diagnostics
+	if profile := loadProfile(context.Background(), "", &diags); profile != (xataProfile{}) || diags.HasError() {
+		t.Fatalf("loadProfile() without credentials file = %+v, %v", profile, diags)
+	}
+
+	path := filepath.Join(home, ".config", "xata", "credentials")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testCredentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if profile := loadProfile(context.Background(), "", &diags); profile.apiKey != "xau_default" || diags.HasError() {
+		t.Fatalf("loadProfile() = %+v, %v", profile, diags)
+	}
+	if profile := loadProfile(context.Background(), "staging", &diags); profile.region != "eu-west-1" || diags.HasError() {
+		t.Fatalf("loadProfile(staging) = %+v, %v", profile, diags)
+	}
+	if loadProfile(context.Background(), "production", &diags); !diags.HasError() {
+		t.Fatal("loadProfile() accepted a missing profile")
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &databaseResource{}
+	_ resource.ResourceWithConfigure   = &databaseResource{}
+	_ resource.ResourceWithImportState = &databaseResource{}
+	_ resource.ResourceWithModifyPlan  = &databaseResource{}
+)
+
+// NewDatabaseResource is a helper function to simplify the provider implementation.
+func NewDatabaseResource() resource.Resource {
+	return &databaseResource{}
+}
+
+// databaseResource is the resource implementation.
+type databaseResource struct {
+	client *xataClient
+}
+
+// databaseResourceModel maps the resource schema data.
+type databaseResourceModel struct {
+	Id            types.String   `tfsdk:"id"`
+	WorkspaceId   types.String   `tfsdk:"workspace_id"`
+	Name          types.String   `tfsdk:"name"`
+	Region        types.String   `tfsdk:"region"`
+	DefaultBranch types.String   `tfsdk:"default_branch"`
+	UIColor       types.String   `tfsdk:"ui_color"`
+	CreatedAt     types.String   `tfsdk:"created_at"`
+	LastUpdated   types.String   `tfsdk:"last_updated"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *databaseResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *databaseResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *databaseResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a database inside a workspace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the database in the form workspace_id/name.",
+				Computed:    true,
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace the database belongs to. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the database. Changing it renames the database in place.",
+				Required:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database is hosted. Defaults to the provider region, or the workspace default region if none is set.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"default_branch": schema.StringAttribute{
+				Description: "Name of the branch created together with the database. Defaults to main.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("main"),
+				PlanModifiers: []planmodifier.String{
+					// Imported databases take their oldest branch as default
+					// branch, those without any branch have no value to
+					// compare against.
+					stringplanmodifier.RequiresReplaceIf(
+						func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = !req.StateValue.IsNull()
+						},
+						"Changing the default branch of an existing database requires replacement.",
+						"Changing the default branch of an existing database requires replacement.",
+					),
+				},
+			},
+			"ui_color": schema.StringAttribute{
+				Description: "Color of the database in the Xata user interface.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Description: "Timestamp of the creation of the database.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the database.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ModifyPlan sets the attributes left unset to the provider defaults.
+func (r *databaseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id")
+}
+
+// Create a new resource.
+func (r *databaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan databaseResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	databaseRequest := xata.CreateDatabaseRequest{
+		DatabaseName: plan.Name.ValueString(),
+		WorkspaceID:  xata.String(plan.WorkspaceId.ValueString()),
+		BranchName:   xata.String(plan.DefaultBranch.ValueString()),
+	}
+	if !plan.Region.IsUnknown() && !plan.Region.IsNull() {
+		databaseRequest.Region = xata.String(plan.Region.ValueString())
+	} else if r.client.region != "" {
+		databaseRequest.Region = xata.String(r.client.region)
+	}
+	if !plan.UIColor.IsUnknown() && !plan.UIColor.IsNull() {
+		databaseRequest.UI = &xata.UI{Color: xata.String(plan.UIColor.ValueString())}
+	}
+
+	// Create new database
+	_, err := r.client.databases.Create(ctx, databaseRequest)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata database",
+			fmt.Sprintf("Could not create database, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Populate Computed attribute values from the created database
+	found, err := r.readDatabase(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata database",
+			fmt.Sprintf("Could not read database %q after creation, unexpected error: %s", plan.Name.ValueString(), err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata database",
+			fmt.Sprintf("Database %q was created but could not be found in workspace %q.", plan.Name.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *databaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state databaseResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get existing database for the given workspace and name
+	found, err := r.readDatabase(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata database",
+			fmt.Sprintf("Could not read database, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Imported databases do not know their default branch yet
+	if state.DefaultBranch.IsNull() {
+		branchClient, ok := workspaceClient[xata.BranchClient](r.client, state.WorkspaceId.ValueString(), state.Region.ValueString(), &resp.Diagnostics)
+		if !ok {
+			return
+		}
+		defaultBranch, err := initialBranch(ctx, branchClient, state.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Xata database",
+				fmt.Sprintf("Could not read the branches of database %q, unexpected error: %s", state.Name.ValueString(), err.Error()),
+			)
+			return
+		}
+		state.DefaultBranch = stringValueOrNull(defaultBranch)
+	}
+
+	// Return database info
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *databaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state
+	var plan, state databaseResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Rename the database if its name changed
+	if !plan.Name.Equal(state.Name) {
+		_, err := r.client.databases.Rename(ctx, xata.RenameDatabaseRequest{
+			DatabaseName: state.Name.ValueString(),
+			NewName:      plan.Name.ValueString(),
+			WorkspaceID:  xata.String(state.WorkspaceId.ValueString()),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Database",
+				fmt.Sprintf("Could not rename database, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	// Update the user interface color if it changed
+	if !plan.UIColor.IsUnknown() && !plan.UIColor.Equal(state.UIColor) {
+		err := r.client.api.UpdateDatabaseMetadata(ctx, plan.WorkspaceId.ValueString(), plan.Name.ValueString(), updateDatabaseMetadataRequest{
+			UI: &databaseUI{Color: xata.String(plan.UIColor.ValueString())},
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Database",
+				fmt.Sprintf("Could not update database metadata, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	// Populate Computed attribute values from the updated database
+	found, err := r.readDatabase(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata database",
+			fmt.Sprintf("Could not read database after update, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata database",
+			fmt.Sprintf("Database %q could not be found in workspace %q after update.", plan.Name.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *databaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state databaseResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete database
+	_, err := r.client.databases.Delete(ctx, xata.DeleteDatabaseRequest{
+		DatabaseName: state.Name.ValueString(),
+		WorkspaceID:  xata.String(state.WorkspaceId.ValueString()),
+	})
+	// A database deleted outside Terraform is already gone
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Xata Database",
+			fmt.Sprintf("Could not delete database, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+}
+
+func (r *databaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID and database name
+	workspaceID, name, ok := strings.Cut(req.ID, "/")
+	if !ok || workspaceID == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/database_name. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// readDatabase looks up the database named in model and maps the API
+// response onto it. It reports false if the database does not exist.
+func (r *databaseResource) readDatabase(ctx context.Context, model *databaseResourceModel) (bool, error) {
+	databaseList, err := r.client.databases.ListWithWorkspaceID(ctx, model.WorkspaceId.ValueString())
+	if err != nil {
+		return false, err
+	}
+
+	for _, database := range databaseList.Databases {
+		if database.Name != model.Name.ValueString() {
+			continue
+		}
+
+		model.Id = types.StringValue(model.WorkspaceId.ValueString() + "/" + database.Name)
+		model.Region = types.StringValue(database.Region)
+		model.CreatedAt = types.StringValue(database.CreatedAt.Format(time.RFC3339))
+		model.UIColor = types.StringNull()
+		if database.Ui != nil && database.Ui.Color != nil {
+			model.UIColor = types.StringValue(*database.Ui.Color)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// initialBranch returns the name of the branch created together with a
+// database, which the Xata API does not report, as its oldest branch. It
+// returns an empty string when the database has no branch.
+func initialBranch(ctx context.Context, branchClient xata.BranchClient, dbName string) (string, error) {
+	branchList, err := branchClient.List(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+
+	var oldest string
+	var oldestCreatedAt time.Time
+	for _, branch := range branchList.Branches {
+		if oldest == "" || branch.CreatedAt.Before(oldestCreatedAt) {
+			oldest, oldestCreatedAt = branch.Name, branch.CreatedAt
+		}
+	}
+
+	return oldest, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDatabaseResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+  ui_color     = "xata-orange"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify created database has Computed attributes filled.
+					resource.TestCheckResourceAttr("xata_database.inventory", "name", "inventory"),
+					resource.TestCheckResourceAttr("xata_database.inventory", "region", "us-east-1"),
+					resource.TestCheckResourceAttr("xata_database.inventory", "default_branch", "main"),
+					resource.TestCheckResourceAttr("xata_database.inventory", "ui_color", "xata-orange"),
+					resource.TestCheckResourceAttrPair("xata_database.inventory", "workspace_id", "xata_workspace.markspace", "id"),
+					resource.TestCheckResourceAttrSet("xata_database.inventory", "id"),
+					resource.TestCheckResourceAttrSet("xata_database.inventory", "created_at"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xata_database.inventory",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The last_updated attribute is not returned by the Xata
+				// API, therefore there is no value for it during import.
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "warehouse"
+  region       = "us-east-1"
+  ui_color     = "xata-blue"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_database.inventory", "name", "warehouse"),
+					resource.TestCheckResourceAttr("xata_database.inventory", "ui_color", "xata-blue"),
+					resource.TestCheckResourceAttr("xata_database.inventory", "region", "us-east-1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &databasesDataSource{}
+	_ datasource.DataSourceWithConfigure = &databasesDataSource{}
+)
+
+// databasesDataSourceModel maps the data source schema data.
+type databasesDataSourceModel struct {
+	WorkspaceId types.String     `tfsdk:"workspace_id"`
+	Databases   []databasesModel `tfsdk:"databases"`
+	Names       []types.String   `tfsdk:"names"`
+}
+
+// databasesModel maps databases schema data.
+type databasesModel struct {
+	Name        types.String `tfsdk:"name"`
+	Region      types.String `tfsdk:"region"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	BranchCount types.Int64  `tfsdk:"branch_count"`
+	UIColor     types.String `tfsdk:"ui_color"`
+}
+
+// databasesDataSource is the data source implementation.
+type databasesDataSource struct {
+	client *xataClient
+}
+
+// NewDatabasesDataSource is a helper function to simplify the provider implementation.
+func NewDatabasesDataSource() datasource.DataSource {
+	return &databasesDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *databasesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_databases"
+}
+
+// Schema defines the schema for the data source.
+func (d *databasesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of databases of a workspace, sorted by name.",
+		Attributes: map[string]schema.Attribute{
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace. Defaults to the provider workspace_id.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"names": schema.ListAttribute{
+				Description: "Names of the databases, in the order of databases.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"databases": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of each database.",
+							Computed:    true,
+						},
+						"region": schema.StringAttribute{
+							Description: "Region of each database.",
+							Computed:    true,
+						},
+						"created_at": schema.StringAttribute{
+							Description: "Creation timestamp of each database.",
+							Computed:    true,
+						},
+						"branch_count": schema.Int64Attribute{
+							Description: "Number of branches of each database.",
+							Computed:    true,
+						},
+						"ui_color": schema.StringAttribute{
+							Description: "Color of each database in the Xata UI, if set.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *databasesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *databasesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state databasesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applyDataSourceDefault(d.client, "workspace_id", &state.WorkspaceId, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	workspaceID := state.WorkspaceId.ValueString()
+
+	databaseList, err := d.client.databases.ListWithWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Databases",
+			fmt.Sprintf("Could not list the databases of workspace %q, unexpected error: %s", workspaceID, err.Error()),
+		)
+		return
+	}
+
+	// Map response body to model, resolving the branch client of every
+	// region up front since diagnostics are not safe for concurrent use
+	branchClients := make([]xata.BranchClient, 0, len(databaseList.Databases))
+	for _, database := range databaseList.Databases {
+		branches, ok := workspaceClient[xata.BranchClient](d.client, workspaceID, database.Region, &resp.Diagnostics)
+		if !ok {
+			return
+		}
+		branchClients = append(branchClients, branches)
+
+		databaseState := databasesModel{
+			Name:      types.StringValue(database.Name),
+			Region:    types.StringValue(database.Region),
+			CreatedAt: types.StringValue(database.CreatedAt.Format(time.RFC3339)),
+			UIColor:   types.StringNull(),
+		}
+		if database.Ui != nil && database.Ui.Color != nil {
+			databaseState.UIColor = types.StringValue(*database.Ui.Color)
+		}
+
+		state.Databases = append(state.Databases, databaseState)
+	}
+
+	// The list omits the branches of the databases, count them concurrently
+	err = forEachConcurrently(ctx, d.client.concurrency(), len(state.Databases), func(ctx context.Context, i int) error {
+		database := &state.Databases[i]
+		branchList, err := branchClients[i].List(ctx, database.Name.ValueString())
+		if err != nil {
+			return fmt.Errorf("database %s: %w", database.Name.ValueString(), err)
+		}
+		database.BranchCount = types.Int64Value(int64(len(branchList.Branches)))
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Database Branches",
+			err.Error(),
+		)
+		return
+	}
+
+	sort.SliceStable(state.Databases, func(i, j int) bool {
+		return state.Databases[i].Name.ValueString() < state.Databases[j].Name.ValueString()
+	})
+
+	state.Names = make([]types.String, 0, len(state.Databases))
+	for _, database := range state.Databases {
+		state.Names = append(state.Names, database.Name)
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
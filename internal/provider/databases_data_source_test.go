@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDatabasesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "warehouse" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "warehouse"
+  region       = "us-east-1"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+  ui_color     = "xata-orange"
+}
+
+resource "xata_branch" "preview" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "preview"
+  from         = "main"
+}
+
+data "xata_databases" "test" {
+  workspace_id = xata_workspace.markspace.id
+
+  depends_on = [xata_database.warehouse, xata_branch.preview]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.0.name", "inventory"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.0.region", "us-east-1"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.0.ui_color", "xata-orange"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.0.branch_count", "2"),
+					resource.TestCheckResourceAttrSet("data.xata_databases.test", "databases.0.created_at"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.1.name", "warehouse"),
+					resource.TestCheckNoResourceAttr("data.xata_databases.test", "databases.1.ui_color"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "databases.1.branch_count", "1"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_databases.test", "names.1", "warehouse"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,1253 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// mockAPIKey is the API key accepted by the mock Xata API.
+const mockAPIKey = "xau_mock"
+
+// mockOwner is the user owning every workspace created on the mock Xata API.
+var mockOwner = workspaceMember{
+	UserID:   "usr_mockowner",
+	Fullname: "Mock Owner",
+	Email:    "owner@example.com",
+	Role:     "owner",
+}
+
+// mockServer is a stateful in-memory fake of the Xata API. It serves both
+// the control plane and the workspace endpoints from the same URL, which
+// the provider targets through its base_url attribute. Because workspace
+// endpoints carry no workspace identifier once served from a single host,
+// databases are looked up by name across all workspaces.
+type mockServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	sequence   int
+	workspaces map[string]*mockWorkspace
+}
+
+type mockWorkspace struct {
+	id        string
+	uniqueID  string
+	name      string
+	slug      string
+	plan      string
+	createdAt time.Time
+	members   []workspaceMember
+	invites   []workspaceInvite
+	databases map[string]*mockDatabase
+
+	// billingRequired makes upgrades fail as if the workspace had no
+	// payment method.
+	billingRequired bool
+}
+
+type mockDatabase struct {
+	name            string
+	region          string
+	createdAt       time.Time
+	color           *string
+	postgresEnabled bool
+	branches        map[string]*mockBranch
+}
+
+type mockBranch struct {
+	name          string
+	createdAt     time.Time
+	metadata      *branchMetadata
+	startedFrom   string
+	tables        []schemaTable
+	pgrollVersion string
+}
+
+// newMockServer starts a mock Xata API. Callers must Close it.
+func newMockServer() *mockServer {
+	m := &mockServer{workspaces: map[string]*mockWorkspace{}}
+
+	mux := http.NewServeMux()
+
+	// Control plane
+	mux.HandleFunc("GET /workspaces", m.listWorkspaces)
+	mux.HandleFunc("POST /workspaces", m.createWorkspace)
+	mux.HandleFunc("GET /workspaces/{ws}", m.getWorkspace)
+	mux.HandleFunc("PUT /workspaces/{ws}", m.updateWorkspace)
+	mux.HandleFunc("PATCH /workspaces/{ws}", m.updateWorkspacePlan)
+	mux.HandleFunc("DELETE /workspaces/{ws}", m.deleteWorkspace)
+	mux.HandleFunc("GET /workspaces/{ws}/members", m.listMembers)
+	mux.HandleFunc("PUT /workspaces/{ws}/members/{user}", m.updateMemberRole)
+	mux.HandleFunc("DELETE /workspaces/{ws}/members/{user}", m.removeMember)
+	mux.HandleFunc("POST /workspaces/{ws}/invites", m.createInvite)
+	mux.HandleFunc("PATCH /workspaces/{ws}/invites/{invite}", m.updateInvite)
+	mux.HandleFunc("DELETE /workspaces/{ws}/invites/{invite}", m.cancelInvite)
+	mux.HandleFunc("POST /workspaces/{ws}/invites/{invite}/resend", m.resendInvite)
+	mux.HandleFunc("GET /workspaces/{ws}/dbs", m.listDatabases)
+	mux.HandleFunc("GET /workspaces/{ws}/dbs/{db}", m.getDatabase)
+	mux.HandleFunc("PUT /workspaces/{ws}/dbs/{db}", m.createDatabase)
+	mux.HandleFunc("PATCH /workspaces/{ws}/dbs/{db}", m.updateDatabase)
+	mux.HandleFunc("DELETE /workspaces/{ws}/dbs/{db}", m.deleteDatabase)
+	mux.HandleFunc("POST /workspaces/{ws}/dbs/{db}/rename", m.renameDatabase)
+
+	// Workspace endpoints
+	mux.HandleFunc("GET /dbs/{db}", m.listBranches)
+	mux.HandleFunc("GET /db/{dbBranch}", m.getBranch)
+	mux.HandleFunc("PUT /db/{dbBranch}", m.createBranch)
+	mux.HandleFunc("DELETE /db/{dbBranch}", m.deleteBranch)
+	mux.HandleFunc("PUT /db/{dbBranch}/metadata", m.updateBranchMetadata)
+	mux.HandleFunc("POST /db/{dbBranch}/schema/update", m.updateBranchSchema)
+	mux.HandleFunc("POST /db/{dbBranch}/pgroll/apply", m.applyPgrollMigration)
+	mux.HandleFunc("GET /db/{dbBranch}/pgroll/status", m.getPgrollStatus)
+	mux.HandleFunc("PUT /db/{dbBranch}/tables/{table}", m.createTable)
+	mux.HandleFunc("PATCH /db/{dbBranch}/tables/{table}", m.renameTable)
+	mux.HandleFunc("DELETE /db/{dbBranch}/tables/{table}", m.deleteTable)
+	mux.HandleFunc("GET /db/{dbBranch}/tables/{table}/schema", m.getTableSchema)
+	mux.HandleFunc("POST /db/{dbBranch}/tables/{table}/columns", m.addColumn)
+	mux.HandleFunc("DELETE /db/{dbBranch}/tables/{table}/columns/{column}", m.deleteColumn)
+
+	m.Server = httptest.NewServer(m.authenticate(mux))
+	return m
+}
+
+// providerConfig returns a provider block targeting the mock server.
+func (m *mockServer) providerConfig() string {
+	return fmt.Sprintf(`
+	provider "xata" {
+	  apikey   = %q
+	  base_url = %q
+	}
+	`, mockAPIKey, m.URL)
+}
+
+// addMember makes a user a member of the workspace with the given name, as
+// if the user had accepted an invite.
+func (m *mockServer) addMember(workspaceName string, member workspaceMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, workspace := range m.workspaces {
+		if workspace.name == workspaceName {
+			workspace.members = append(workspace.members, member)
+			return nil
+		}
+	}
+	return fmt.Errorf("workspace %q not found", workspaceName)
+}
+
+// requireBilling makes the workspace with the given name refuse upgrades
+// from the free tier, as if it had no payment method.
+func (m *mockServer) requireBilling(workspaceName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, workspace := range m.workspaces {
+		if workspace.name == workspaceName {
+			workspace.billingRequired = true
+			return nil
+		}
+	}
+	return fmt.Errorf("workspace %q not found", workspaceName)
+}
+
+// enablePostgres turns a database with the given name into a Postgres
+// enabled database, whose branches accept pgroll migrations. Databases
+// cannot be converted through the Xata API.
+func (m *mockServer) enablePostgres(databaseName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, workspace := range m.workspaces {
+		if database, ok := workspace.databases[databaseName]; ok {
+			database.postgresEnabled = true
+			return nil
+		}
+	}
+	return fmt.Errorf("database %q not found", databaseName)
+}
+
+func (m *mockServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+mockAPIKey {
+			mockError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func mockJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func mockError(w http.ResponseWriter, status int, message string) {
+	mockJSON(w, status, map[string]string{"id": http.StatusText(status), "message": message})
+}
+
+func mockDecode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		mockError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+var mockSlugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+func mockSlug(name string) string {
+	return strings.Trim(mockSlugInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func (m *mockServer) nextID(prefix string) string {
+	m.sequence++
+	return fmt.Sprintf("%s%06d", prefix, m.sequence)
+}
+
+func (m *mockServer) workspace(w http.ResponseWriter, r *http.Request) (*mockWorkspace, bool) {
+	workspace, ok := m.workspaces[r.PathValue("ws")]
+	if !ok {
+		mockError(w, http.StatusNotFound, fmt.Sprintf("workspace %s not found", r.PathValue("ws")))
+	}
+	return workspace, ok
+}
+
+func (ws *mockWorkspace) response() map[string]any {
+	return map[string]any{
+		"id":          ws.id,
+		"name":        ws.name,
+		"slug":        ws.slug,
+		"memberCount": len(ws.members),
+		"plan":        ws.plan,
+		"unique_id":   ws.uniqueID,
+		"createdAt":   ws.createdAt,
+		"settings":    map[string]any{"postgresEnabled": false},
+	}
+}
+
+func (m *mockServer) listWorkspaces(w http.ResponseWriter, _ *http.Request) {
+	workspaces := []map[string]any{}
+	for _, workspace := range m.workspaces {
+		workspaces = append(workspaces, map[string]any{
+			"id":   workspace.id,
+			"name": workspace.name,
+			"slug": workspace.slug,
+			"role": "owner",
+			"plan": workspace.plan,
+		})
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i]["name"].(string) < workspaces[j]["name"].(string)
+	})
+	mockJSON(w, http.StatusOK, map[string]any{"workspaces": workspaces})
+}
+
+type mockWorkspaceMeta struct {
+	Name string  `json:"name"`
+	Slug *string `json:"slug"`
+}
+
+func (m *mockServer) createWorkspace(w http.ResponseWriter, r *http.Request) {
+	var request mockWorkspaceMeta
+	if !mockDecode(w, r, &request) {
+		return
+	}
+	if request.Name == "" {
+		mockError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	slug := mockSlug(request.Name)
+	if request.Slug != nil && *request.Slug != "" {
+		slug = *request.Slug
+	}
+	workspace := &mockWorkspace{
+		id:        m.nextID(slug + "-"),
+		uniqueID:  m.nextID("wsu_"),
+		name:      request.Name,
+		slug:      slug,
+		plan:      "free",
+		createdAt: time.Now().UTC().Truncate(time.Second),
+		members:   []workspaceMember{mockOwner},
+		databases: map[string]*mockDatabase{},
+	}
+	m.workspaces[workspace.id] = workspace
+
+	mockJSON(w, http.StatusCreated, workspace.response())
+}
+
+func (m *mockServer) getWorkspace(w http.ResponseWriter, r *http.Request) {
+	if workspace, ok := m.workspace(w, r); ok {
+		mockJSON(w, http.StatusOK, workspace.response())
+	}
+}
+
+func (m *mockServer) updateWorkspace(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+	var request mockWorkspaceMeta
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	workspace.name = request.Name
+	if request.Slug != nil && *request.Slug != "" {
+		workspace.slug = *request.Slug
+	}
+	mockJSON(w, http.StatusOK, workspace.response())
+}
+
+func (m *mockServer) updateWorkspacePlan(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+	var request updateWorkspacePlanRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	switch request.Plan {
+	case "pro":
+		if workspace.billingRequired {
+			mockError(w, http.StatusPaymentRequired, "a billing account is required to upgrade the workspace")
+			return
+		}
+		workspace.plan = request.Plan
+		mockJSON(w, http.StatusOK, workspace.response())
+	case "free":
+		workspace.plan = request.Plan
+		mockJSON(w, http.StatusOK, workspace.response())
+	default:
+		mockError(w, http.StatusBadRequest, fmt.Sprintf("unknown plan %q", request.Plan))
+	}
+}
+
+func (m *mockServer) deleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	if _, ok := m.workspace(w, r); ok {
+		delete(m.workspaces, r.PathValue("ws"))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (m *mockServer) listMembers(w http.ResponseWriter, r *http.Request) {
+	if workspace, ok := m.workspace(w, r); ok {
+		mockJSON(w, http.StatusOK, workspaceMembers{Members: workspace.members, Invites: workspace.invites})
+	}
+}
+
+func (m *mockServer) updateMemberRole(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+	var request updateWorkspaceMemberRoleRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	for i := range workspace.members {
+		if workspace.members[i].UserID == r.PathValue("user") {
+			workspace.members[i].Role = request.Role
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	mockError(w, http.StatusNotFound, fmt.Sprintf("member %s not found", r.PathValue("user")))
+}
+
+func (m *mockServer) removeMember(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+
+	for i := range workspace.members {
+		if workspace.members[i].UserID == r.PathValue("user") {
+			workspace.members = append(workspace.members[:i], workspace.members[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	mockError(w, http.StatusNotFound, fmt.Sprintf("member %s not found", r.PathValue("user")))
+}
+
+func (m *mockServer) invite(w http.ResponseWriter, r *http.Request) (*mockWorkspace, int) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return nil, -1
+	}
+	for i := range workspace.invites {
+		if workspace.invites[i].InviteID == r.PathValue("invite") {
+			return workspace, i
+		}
+	}
+	mockError(w, http.StatusNotFound, fmt.Sprintf("invite %s not found", r.PathValue("invite")))
+	return nil, -1
+}
+
+func (m *mockServer) createInvite(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+	var request inviteWorkspaceMemberRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	invite := workspaceInvite{
+		InviteID: m.nextID("inv_"),
+		Email:    request.Email,
+		Role:     request.Role,
+		Expires:  time.Now().UTC().Add(7 * 24 * time.Hour).Truncate(time.Second),
+	}
+	workspace.invites = append(workspace.invites, invite)
+	mockJSON(w, http.StatusCreated, invite)
+}
+
+func (m *mockServer) updateInvite(w http.ResponseWriter, r *http.Request) {
+	workspace, i := m.invite(w, r)
+	if workspace == nil {
+		return
+	}
+	var request updateWorkspaceInviteRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	workspace.invites[i].Role = request.Role
+	mockJSON(w, http.StatusOK, workspace.invites[i])
+}
+
+func (m *mockServer) cancelInvite(w http.ResponseWriter, r *http.Request) {
+	if workspace, i := m.invite(w, r); workspace != nil {
+		workspace.invites = append(workspace.invites[:i], workspace.invites[i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (m *mockServer) resendInvite(w http.ResponseWriter, r *http.Request) {
+	if workspace, i := m.invite(w, r); workspace != nil {
+		workspace.invites[i].Expires = time.Now().UTC().Add(7 * 24 * time.Hour).Truncate(time.Second)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (db *mockDatabase) response() map[string]any {
+	response := map[string]any{
+		"name":            db.name,
+		"region":          db.region,
+		"createdAt":       db.createdAt,
+		"postgresEnabled": db.postgresEnabled,
+	}
+	if db.color != nil {
+		response["ui"] = map[string]any{"color": *db.color}
+	}
+	return response
+}
+
+func (m *mockServer) database(w http.ResponseWriter, r *http.Request) (*mockWorkspace, *mockDatabase, bool) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	database, ok := workspace.databases[r.PathValue("db")]
+	if !ok {
+		mockError(w, http.StatusNotFound, fmt.Sprintf("database %s not found", r.PathValue("db")))
+	}
+	return workspace, database, ok
+}
+
+func (m *mockServer) listDatabases(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+
+	databases := []map[string]any{}
+	for _, database := range workspace.databases {
+		databases = append(databases, database.response())
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		return databases[i]["name"].(string) < databases[j]["name"].(string)
+	})
+	mockJSON(w, http.StatusOK, map[string]any{"databases": databases})
+}
+
+func (m *mockServer) getDatabase(w http.ResponseWriter, r *http.Request) {
+	if _, database, ok := m.database(w, r); ok {
+		mockJSON(w, http.StatusOK, database.response())
+	}
+}
+
+func (m *mockServer) createDatabase(w http.ResponseWriter, r *http.Request) {
+	workspace, ok := m.workspace(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		BranchName      *string         `json:"branchName"`
+		Region          string          `json:"region"`
+		UI              *databaseUI     `json:"ui"`
+		Metadata        *branchMetadata `json:"metadata"`
+		PostgresEnabled bool            `json:"postgresEnabled"`
+	}
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	name := r.PathValue("db")
+	if _, exists := workspace.databases[name]; exists {
+		mockError(w, http.StatusConflict, fmt.Sprintf("database %s already exists", name))
+		return
+	}
+	region := request.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	branchName := "main"
+	if request.BranchName != nil && *request.BranchName != "" {
+		branchName = *request.BranchName
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	database := &mockDatabase{
+		name:            name,
+		region:          region,
+		createdAt:       now,
+		postgresEnabled: request.PostgresEnabled,
+		branches: map[string]*mockBranch{
+			branchName: {name: branchName, createdAt: now, metadata: request.Metadata},
+		},
+	}
+	if request.UI != nil {
+		database.color = request.UI.Color
+	}
+	workspace.databases[name] = database
+
+	mockJSON(w, http.StatusCreated, map[string]any{"databaseName": name, "branchName": branchName, "status": "completed"})
+}
+
+func (m *mockServer) updateDatabase(w http.ResponseWriter, r *http.Request) {
+	_, database, ok := m.database(w, r)
+	if !ok {
+		return
+	}
+	var request updateDatabaseMetadataRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	if request.UI != nil {
+		database.color = request.UI.Color
+	}
+	mockJSON(w, http.StatusOK, database.response())
+}
+
+func (m *mockServer) deleteDatabase(w http.ResponseWriter, r *http.Request) {
+	if workspace, database, ok := m.database(w, r); ok {
+		delete(workspace.databases, database.name)
+		mockJSON(w, http.StatusOK, map[string]any{"status": "completed"})
+	}
+}
+
+func (m *mockServer) renameDatabase(w http.ResponseWriter, r *http.Request) {
+	workspace, database, ok := m.database(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		NewName string `json:"newName"`
+	}
+	if !mockDecode(w, r, &request) {
+		return
+	}
+	if _, exists := workspace.databases[request.NewName]; exists {
+		mockError(w, http.StatusConflict, fmt.Sprintf("database %s already exists", request.NewName))
+		return
+	}
+
+	delete(workspace.databases, database.name)
+	database.name = request.NewName
+	workspace.databases[database.name] = database
+	mockJSON(w, http.StatusOK, database.response())
+}
+
+// lookupDatabase finds a database by name in any workspace.
+func (m *mockServer) lookupDatabase(w http.ResponseWriter, name string) (*mockDatabase, bool) {
+	for _, workspace := range m.workspaces {
+		if database, ok := workspace.databases[name]; ok {
+			return database, true
+		}
+	}
+	mockError(w, http.StatusNotFound, fmt.Sprintf("database %s not found", name))
+	return nil, false
+}
+
+// branch resolves the db:branch path value. The branch is nil if the
+// database exists but the branch does not.
+func (m *mockServer) branch(w http.ResponseWriter, r *http.Request) (*mockDatabase, string, *mockBranch, bool) {
+	dbName, branchName, _ := strings.Cut(r.PathValue("dbBranch"), ":")
+	if branchName == "" {
+		branchName = "main"
+	}
+	database, ok := m.lookupDatabase(w, dbName)
+	if !ok {
+		return nil, "", nil, false
+	}
+	return database, branchName, database.branches[branchName], true
+}
+
+func (m *mockServer) existingBranch(w http.ResponseWriter, r *http.Request) (*mockDatabase, *mockBranch, bool) {
+	database, name, branch, ok := m.branch(w, r)
+	if ok && branch == nil {
+		mockError(w, http.StatusNotFound, fmt.Sprintf("branch %s not found", name))
+		return nil, nil, false
+	}
+	return database, branch, ok
+}
+
+func (m *mockServer) listBranches(w http.ResponseWriter, r *http.Request) {
+	database, ok := m.lookupDatabase(w, r.PathValue("db"))
+	if !ok {
+		return
+	}
+
+	branches := []map[string]any{}
+	for _, branch := range database.branches {
+		branches = append(branches, map[string]any{"name": branch.name, "createdAt": branch.createdAt})
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i]["name"].(string) < branches[j]["name"].(string)
+	})
+	mockJSON(w, http.StatusOK, map[string]any{"databaseName": database.name, "branches": branches})
+}
+
+func (m *mockServer) getBranch(w http.ResponseWriter, r *http.Request) {
+	database, branch, ok := m.existingBranch(w, r)
+	if !ok {
+		return
+	}
+
+	response := map[string]any{
+		"databaseName":    database.name,
+		"branchName":      branch.name,
+		"createdAt":       branch.createdAt,
+		"id":              "bb_" + database.name + "_" + branch.name,
+		"lastMigrationID": "",
+		"version":         1,
+		"schema":          branchSchema{Tables: append([]schemaTable{}, branch.tables...)},
+	}
+	if branch.metadata != nil {
+		response["metadata"] = branch.metadata
+	}
+	if branch.startedFrom != "" {
+		response["startedFrom"] = map[string]any{
+			"branchName":  branch.startedFrom,
+			"dbBranchID":  "bb_" + database.name + "_" + branch.startedFrom,
+			"migrationID": "",
+		}
+	}
+	mockJSON(w, http.StatusOK, response)
+}
+
+func (m *mockServer) createBranch(w http.ResponseWriter, r *http.Request) {
+	database, name, branch, ok := m.branch(w, r)
+	if !ok {
+		return
+	}
+	if branch != nil {
+		mockError(w, http.StatusConflict, fmt.Sprintf("branch %s already exists", name))
+		return
+	}
+	var request struct {
+		From     *string         `json:"from"`
+		Metadata *branchMetadata `json:"metadata"`
+	}
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	// Like the Xata API, an explicit empty parent is looked up rather than
+	// treated as no parent
+	from, hasFrom := r.URL.Query().Get("from"), r.URL.Query().Has("from")
+	if request.From != nil {
+		from, hasFrom = *request.From, true
+	}
+	created := &mockBranch{name: name, createdAt: time.Now().UTC().Truncate(time.Second), metadata: request.Metadata}
+	if hasFrom {
+		parent, exists := database.branches[from]
+		if !exists {
+			mockError(w, http.StatusNotFound, fmt.Sprintf("branch %s not found", from))
+			return
+		}
+		created.startedFrom = from
+		created.tables = copySchemaTables(parent.tables)
+	}
+	database.branches[name] = created
+
+	mockJSON(w, http.StatusCreated, map[string]any{"databaseName": database.name, "branchName": name, "status": "completed"})
+}
+
+func (m *mockServer) deleteBranch(w http.ResponseWriter, r *http.Request) {
+	if database, branch, ok := m.existingBranch(w, r); ok {
+		delete(database.branches, branch.name)
+		mockJSON(w, http.StatusOK, map[string]any{"status": "completed"})
+	}
+}
+
+func (m *mockServer) updateBranchMetadata(w http.ResponseWriter, r *http.Request) {
+	_, branch, ok := m.existingBranch(w, r)
+	if !ok {
+		return
+	}
+	var request branchMetadata
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	branch.metadata = &request
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) updateBranchSchema(w http.ResponseWriter, r *http.Request) {
+	_, branch, ok := m.existingBranch(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		Operations []map[string]json.RawMessage `json:"operations"`
+	}
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	tables := copySchemaTables(branch.tables)
+	for _, operation := range request.Operations {
+		var err error
+		for kind, raw := range operation {
+			var target struct {
+				Table  string          `json:"table"`
+				Column json.RawMessage `json:"column"`
+			}
+			if err = json.Unmarshal(raw, &target); err != nil {
+				break
+			}
+			tables, err = applyMockOperation(tables, kind, target.Table, target.Column)
+		}
+		if err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	branch.tables = tables
+	mockJSON(w, http.StatusOK, map[string]any{"migrationID": m.nextID("mig_"), "parentMigrationID": "", "status": "completed"})
+}
+
+func applyMockOperation(tables []schemaTable, kind string, table string, column json.RawMessage) ([]schemaTable, error) {
+	index := -1
+	for i := range tables {
+		if tables[i].Name == table {
+			index = i
+		}
+	}
+
+	switch {
+	case kind == "addTable" && index < 0:
+		return append(tables, schemaTable{Name: table}), nil
+	case kind == "removeTable" && index >= 0:
+		return append(tables[:index], tables[index+1:]...), nil
+	case kind == "addColumn" && index >= 0:
+		var added schemaColumn
+		if err := json.Unmarshal(column, &added); err != nil {
+			return nil, err
+		}
+		// Like the Xata API, reject the names of internal columns
+		if strings.HasPrefix(added.Name, "xata") {
+			return nil, fmt.Errorf("column %s.%s: names starting with xata are reserved", table, added.Name)
+		}
+		siblings, name, err := mockNestedColumns(&tables[index].Columns, added.Name)
+		if err != nil {
+			return nil, fmt.Errorf("column %s.%s: %w", table, added.Name, err)
+		}
+		for _, existing := range *siblings {
+			if existing.Name == name {
+				return nil, fmt.Errorf("column %s.%s already exists", table, added.Name)
+			}
+		}
+		added.Name = name
+		*siblings = append(*siblings, added)
+		return tables, nil
+	case kind == "removeColumn" && index >= 0:
+		var path string
+		if err := json.Unmarshal(column, &path); err != nil {
+			return nil, err
+		}
+		siblings, name, err := mockNestedColumns(&tables[index].Columns, path)
+		if err != nil {
+			return nil, fmt.Errorf("column %s.%s: %w", table, path, err)
+		}
+		for i, existing := range *siblings {
+			if existing.Name == name {
+				*siblings = append((*siblings)[:i], (*siblings)[i+1:]...)
+				return tables, nil
+			}
+		}
+		return nil, fmt.Errorf("column %s.%s not found", table, path)
+	}
+	return nil, fmt.Errorf("cannot apply %s to table %s", kind, table)
+}
+
+// mockNestedColumns resolves a dotted column path to the columns of the
+// object column it is nested in and its name within them.
+func mockNestedColumns(columns *[]schemaColumn, path string) (*[]schemaColumn, string, error) {
+	parent, name, found := strings.Cut(path, ".")
+	if !found {
+		return columns, path, nil
+	}
+	for i := range *columns {
+		if (*columns)[i].Name == parent && (*columns)[i].Type == "object" {
+			return mockNestedColumns(&(*columns)[i].Columns, name)
+		}
+	}
+	return nil, "", fmt.Errorf("object column %s not found", parent)
+}
+
+// mockColumn resolves a dotted column path to the column it names.
+func mockColumn(columns []schemaColumn, path string) (*schemaColumn, error) {
+	siblings, name, err := mockNestedColumns(&columns, path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *siblings {
+		if (*siblings)[i].Name == name {
+			return &(*siblings)[i], nil
+		}
+	}
+	return nil, fmt.Errorf("column %s not found", path)
+}
+
+func copySchemaTables(tables []schemaTable) []schemaTable {
+	copied := make([]schemaTable, 0, len(tables))
+	for _, table := range tables {
+		copied = append(copied, schemaTable{Name: table.Name, Columns: copySchemaColumns(table.Columns)})
+	}
+	return copied
+}
+
+func copySchemaColumns(columns []schemaColumn) []schemaColumn {
+	if columns == nil {
+		return nil
+	}
+	copied := make([]schemaColumn, 0, len(columns))
+	for _, column := range columns {
+		column.Columns = copySchemaColumns(column.Columns)
+		copied = append(copied, column)
+	}
+	return copied
+}
+
+// applyPgrollMigration supports the column alterations the provider
+// generates: nullability and default value changes. Like the Xata API, it
+// rejects migrations of databases without Postgres enabled.
+func (m *mockServer) applyPgrollMigration(w http.ResponseWriter, r *http.Request) {
+	database, branch, ok := m.existingBranch(w, r)
+	if !ok {
+		return
+	}
+	if !database.postgresEnabled {
+		mockError(w, http.StatusBadRequest, fmt.Sprintf("database %s does not have postgres enabled", database.name))
+		return
+	}
+	var request struct {
+		Operations []map[string]map[string]any `json:"operations"`
+	}
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	tables := copySchemaTables(branch.tables)
+	for _, operation := range request.Operations {
+		for kind, settings := range operation {
+			table, _ := settings["table"].(string)
+			path, _ := settings["column"].(string)
+			if err := applyMockPgrollOperation(tables, kind, table, path, settings); err != nil {
+				mockError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+	}
+
+	branch.tables = tables
+	branch.pgrollVersion = m.nextID("mig_")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func applyMockPgrollOperation(tables []schemaTable, kind string, table string, path string, settings map[string]any) error {
+	index := slices.IndexFunc(tables, func(candidate schemaTable) bool { return candidate.Name == table })
+	if index < 0 {
+		return fmt.Errorf("table %s not found", table)
+	}
+	column, err := mockColumn(tables[index].Columns, path)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "alter_column":
+		if value, ok := settings["default"]; ok {
+			column.DefaultValue = nil
+			if expression, ok := value.(string); ok {
+				column.DefaultValue = mockDefaultValue(expression)
+			}
+		}
+		if nullable, ok := settings["nullable"].(bool); ok {
+			column.NotNull = nil
+			if !nullable {
+				column.NotNull = xata.Bool(true)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported pgroll operation %s", kind)
+}
+
+// mockDefaultValue converts an SQL default expression back to the Xata
+// default value.
+func mockDefaultValue(expression string) *string {
+	switch {
+	case expression == "now()":
+		return xata.String("now")
+	case strings.HasPrefix(expression, "'") && strings.HasSuffix(expression, "'") && len(expression) > 1:
+		return xata.String(strings.ReplaceAll(expression[1:len(expression)-1], "''", "'"))
+	}
+	return xata.String(expression)
+}
+
+func (m *mockServer) getPgrollStatus(w http.ResponseWriter, r *http.Request) {
+	if _, branch, ok := m.existingBranch(w, r); ok {
+		status := "no migrations"
+		if branch.pgrollVersion != "" {
+			status = "complete"
+		}
+		mockJSON(w, http.StatusOK, pgrollStatus{Status: status, Version: branch.pgrollVersion})
+	}
+}
+
+// table resolves the table path value. The table index is -1 if the branch
+// exists but the table does not.
+func (m *mockServer) table(w http.ResponseWriter, r *http.Request) (*mockBranch, int, bool) {
+	_, branch, ok := m.existingBranch(w, r)
+	if !ok {
+		return nil, -1, false
+	}
+	for i := range branch.tables {
+		if branch.tables[i].Name == r.PathValue("table") {
+			return branch, i, true
+		}
+	}
+	return branch, -1, true
+}
+
+func (m *mockServer) existingTable(w http.ResponseWriter, r *http.Request) (*mockBranch, int, bool) {
+	branch, index, ok := m.table(w, r)
+	if ok && index < 0 {
+		mockError(w, http.StatusNotFound, fmt.Sprintf("table %s not found", r.PathValue("table")))
+		return nil, -1, false
+	}
+	return branch, index, ok
+}
+
+func (m *mockServer) createTable(w http.ResponseWriter, r *http.Request) {
+	branch, index, ok := m.table(w, r)
+	if !ok {
+		return
+	}
+	if index >= 0 {
+		mockError(w, http.StatusConflict, fmt.Sprintf("table %s already exists", r.PathValue("table")))
+		return
+	}
+
+	branch.tables = append(branch.tables, schemaTable{Name: r.PathValue("table")})
+	mockJSON(w, http.StatusCreated, map[string]any{"branchName": branch.name, "tableName": r.PathValue("table"), "status": "completed"})
+}
+
+func (m *mockServer) renameTable(w http.ResponseWriter, r *http.Request) {
+	branch, index, ok := m.existingTable(w, r)
+	if !ok {
+		return
+	}
+	var request updateTableRequest
+	if !mockDecode(w, r, &request) {
+		return
+	}
+
+	branch.tables[index].Name = request.Name
+	mockJSON(w, http.StatusOK, map[string]any{"migrationID": m.nextID("mig_"), "parentMigrationID": "", "status": "completed"})
+}
+
+func (m *mockServer) deleteTable(w http.ResponseWriter, r *http.Request) {
+	if branch, index, ok := m.existingTable(w, r); ok {
+		branch.tables = append(branch.tables[:index], branch.tables[index+1:]...)
+		mockJSON(w, http.StatusOK, map[string]any{"status": "completed"})
+	}
+}
+
+// The table endpoints of xata-go name the file[] column settings fileMap,
+// while schema documents use the column type as key.
+func (m *mockServer) getTableSchema(w http.ResponseWriter, r *http.Request) {
+	branch, index, ok := m.existingTable(w, r)
+	if !ok {
+		return
+	}
+
+	columns := []map[string]any{}
+	for _, column := range branch.tables[index].Columns {
+		var encoded map[string]any
+		raw, _ := json.Marshal(column)
+		_ = json.Unmarshal(raw, &encoded)
+		if fileMap, ok := encoded["file[]"]; ok {
+			delete(encoded, "file[]")
+			encoded["fileMap"] = fileMap
+		}
+		columns = append(columns, encoded)
+	}
+	mockJSON(w, http.StatusOK, map[string]any{"columns": columns})
+}
+
+func (m *mockServer) addColumn(w http.ResponseWriter, r *http.Request) {
+	branch, index, ok := m.existingTable(w, r)
+	if !ok {
+		return
+	}
+	var request map[string]json.RawMessage
+	if !mockDecode(w, r, &request) {
+		return
+	}
+	if fileMap, ok := request["fileMap"]; ok {
+		delete(request, "fileMap")
+		request["file[]"] = fileMap
+	}
+	raw, _ := json.Marshal(request)
+
+	tables, err := applyMockOperation(branch.tables, "addColumn", branch.tables[index].Name, raw)
+	if err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	branch.tables = tables
+	mockJSON(w, http.StatusOK, map[string]any{"migrationID": m.nextID("mig_"), "parentMigrationID": "", "status": "completed"})
+}
+
+func (m *mockServer) deleteColumn(w http.ResponseWriter, r *http.Request) {
+	branch, index, ok := m.existingTable(w, r)
+	if !ok {
+		return
+	}
+	name, _ := json.Marshal(r.PathValue("column"))
+
+	tables, err := applyMockOperation(branch.tables, "removeColumn", branch.tables[index].Name, name)
+	if err != nil {
+		mockError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	branch.tables = tables
+	mockJSON(w, http.StatusOK, map[string]any{"migrationID": m.nextID("mig_"), "parentMigrationID": "", "status": "completed"})
+}
+
+func TestMockServer(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockServer()
+	defer mock.Close()
+
+	client, err := newXataClient(mockAPIKey, mock.URL, mock.URL, "", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspace, err := client.workspaces.Create(ctx, &xata.WorkspaceMeta{Name: "Mark Space"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *workspace.Slug != "mark-space" || workspace.MemberCount != 1 || workspace.Plan.String() != "free" {
+		t.Fatalf("unexpected workspace %+v", workspace)
+	}
+	details, err := client.api.GetWorkspace(ctx, workspace.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.UniqueID == "" || details.MemberCount != 1 || details.CreatedAt == "" || details.Settings["postgresEnabled"] != false {
+		t.Fatalf("unexpected workspace details %+v", details)
+	}
+
+	_, err = client.databases.Create(ctx, xata.CreateDatabaseRequest{
+		DatabaseName: "inventory",
+		WorkspaceID:  xata.String(workspace.Id),
+		Region:       xata.String("eu-west-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	region, found, err := client.databaseRegion(ctx, workspace.Id, "inventory")
+	if err != nil || !found || region != "eu-west-1" {
+		t.Fatalf("databaseRegion() = %q, %v, %v", region, found, err)
+	}
+
+	var diags diag.Diagnostics
+	tables, ok := workspaceClient[xata.TableClient](client, workspace.Id, region, &diags)
+	if !ok {
+		t.Fatal(diags)
+	}
+	request := xata.TableRequest{DatabaseName: xata.String("inventory"), BranchName: xata.String("main"), TableName: "items"}
+	if _, err := tables.Create(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tables.AddColumn(ctx, xata.AddColumnRequest{TableRequest: request, Column: &xata.Column{Name: "title", Type: xata.ColumnTypeString}}); err != nil {
+		t.Fatal(err)
+	}
+	tableSchema, err := tables.GetSchema(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tableSchema.Columns) != 1 || tableSchema.Columns[0].Type.String() != "string" {
+		t.Fatalf("unexpected columns %+v", tableSchema.Columns)
+	}
+
+	for _, column := range []*xata.Column{{Name: "size", Type: xata.ColumnTypeObject}, {Name: "size.width", Type: xata.ColumnTypeFloat}} {
+		if _, err := tables.AddColumn(ctx, xata.AddColumnRequest{TableRequest: request, Column: column}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	operation, err := alterColumnOperation("items", "title", "string", columnSettings{}, columnSettings{
+		NotNull: true, DefaultValue: xata.String("it's"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.api.MigrateBranch(ctx, client.workspaceURL(workspace.Id, region), "inventory:main", []migrationOperation{operation})
+	if statusCode(err) != http.StatusBadRequest {
+		t.Fatalf("expected migrating a database without Postgres enabled to fail, got %v", err)
+	}
+	if err := mock.enablePostgres("inventory"); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := client.api.GetDatabaseMetadata(ctx, workspace.Id, "inventory")
+	if err != nil || !metadata.PostgresEnabled || metadata.Region != "eu-west-1" {
+		t.Fatalf("GetDatabaseMetadata() = %+v, %v", metadata, err)
+	}
+	if err := client.api.MigrateBranch(ctx, client.workspaceURL(workspace.Id, region), "inventory:main", []migrationOperation{operation}); err != nil {
+		t.Fatal(err)
+	}
+	tableSchema, err = tables.GetSchema(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	title, size := tableSchema.Columns[0], tableSchema.Columns[1]
+	if !*title.NotNull || *title.DefaultValue != "it's" {
+		t.Fatalf("column was not altered: %+v", title)
+	}
+	if size.Columns == nil || len(*size.Columns) != 1 || (*size.Columns)[0].Name != "width" {
+		t.Fatalf("unexpected nested columns %+v", size)
+	}
+	if _, err := tables.DeleteColumn(ctx, xata.DeleteColumnRequest{TableRequest: request, ColumnName: "size.width"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tables.Delete(ctx, xata.TableRequest{DatabaseName: xata.String("inventory"), BranchName: xata.String("main"), TableName: "missing"})
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found error deleting a missing table, got %v", err)
+	}
+
+	branches, ok := workspaceClient[xata.BranchClient](client, workspace.Id, region, &diags)
+	if !ok {
+		t.Fatal(diags)
+	}
+	_, err = branches.Create(ctx, xata.CreateBranchRequest{
+		DatabaseName: xata.String("inventory"),
+		BranchName:   "preview",
+		Payload:      &xata.CreateBranchRequestPayload{CreateBranchRequestFrom: xata.String("main")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	branchList, err := branches.List(ctx, "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branchList.Branches) != 2 {
+		t.Fatalf("unexpected branches %+v", branchList.Branches)
+	}
+	live, err := client.api.GetBranchSchema(ctx, client.workspaceURL(workspace.Id, region), "inventory:preview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(live.Tables) != 1 || live.Tables[0].Name != "items" {
+		t.Fatalf("branch was not copied from main: %+v", live)
+	}
+
+	defaultBranch, err := initialBranch(ctx, branches, "inventory")
+	if err != nil || defaultBranch != "main" {
+		t.Fatalf("initialBranch() = %q, %v", defaultBranch, err)
+	}
+
+	_, err = branches.Create(ctx, xata.CreateBranchRequest{
+		DatabaseName: xata.String("inventory"),
+		BranchName:   "empty",
+	})
+	if err != nil {
+		t.Fatalf("creating a branch without parent: %v", err)
+	}
+	for name, from := range map[string]types.String{"preview": types.StringValue("main"), "empty": types.StringNull()} {
+		imported := branchResourceModel{WorkspaceId: types.StringValue(workspace.Id), Database: types.StringValue("inventory"), Name: types.StringValue(name)}
+		if err := (&branchResource{}).readBranch(ctx, branches, &imported); err != nil {
+			t.Fatal(err)
+		}
+		if !imported.From.Equal(from) {
+			t.Fatalf("readBranch() read from = %s for branch %s, want %s", imported.From, name, from)
+		}
+	}
+
+	_, err = client.databases.Delete(ctx, xata.DeleteDatabaseRequest{DatabaseName: "missing", WorkspaceID: xata.String(workspace.Id)})
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found error deleting a missing database, got %v", err)
+	}
+
+	err = client.api.RemoveWorkspaceMember(ctx, workspace.Id, "usr_missing")
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found error removing a missing member, got %v", err)
+	}
+
+	err = client.workspaces.Delete(ctx, workspace.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.workspaces.GetWithWorkspaceID(ctx, workspace.Id)
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
@@ -4,16 +4,27 @@
 package provider
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/xataio/xata-go/xata"
+	"net/http"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -21,9 +32,26 @@ var (
 	_ provider.Provider = &xataProvider{}
 )
 
+// apikeyPattern matches the API keys issued by Xata.
+var apikeyPattern = regexp.MustCompile(`^xau_[A-Za-z0-9]+$`)
+
 // xataProviderModel maps provider schema data to a Go type.
 type xataProviderModel struct {
-	Apikey types.String `tfsdk:"apikey"`
+	Apikey          types.String `tfsdk:"apikey"`
+	ApikeyFile      types.String `tfsdk:"apikey_file"`
+	Profile         types.String `tfsdk:"profile"`
+	BaseURL         types.String `tfsdk:"base_url"`
+	ControlPlaneURL types.String `tfsdk:"control_plane_url"`
+	Region          types.String `tfsdk:"region"`
+	WorkspaceID     types.String `tfsdk:"workspace_id"`
+	Database        types.String `tfsdk:"database"`
+	Branch          types.String `tfsdk:"branch"`
+	MaxRetries      types.Int64  `tfsdk:"max_retries"`
+	MinRetryBackoff types.String `tfsdk:"min_retry_backoff"`
+	MaxRetryBackoff types.String `tfsdk:"max_retry_backoff"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
 }
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -55,9 +83,92 @@ func (p *xataProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 		Description: "Interact with Xata.",
 		Attributes: map[string]schema.Attribute{
 			"apikey": schema.StringAttribute{
-				Description: "API KEY for Xata API. May also be provided via XATA_API_KEY environment variable.",
+				Description: "API KEY for Xata API. May also be provided via XATA_API_KEY environment variable, " +
+					"or read from the profile of the Xata CLI credentials file when neither is set.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(apikeyPattern, "must be a Xata API key, starting with xau_ followed by letters and digits"),
+					stringvalidator.ConflictsWith(path.MatchRoot("apikey_file")),
+				},
+			},
+			"apikey_file": schema.StringAttribute{
+				Description: "Path of a file holding the API KEY for Xata API, for secrets mounted as files. Surrounding whitespace is ignored. " +
+					"Takes precedence over the XATA_API_KEY environment variable, conflicts with apikey.",
+				Optional: true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "Profile of the Xata CLI credentials file, ~/.config/xata/credentials, to read the API key and the region from " +
+					"when they are set neither in the configuration nor in the environment. Defaults to the default profile of `xata auth login`, " +
+					"which is only read when no API key is set otherwise. May also be provided via XATA_PROFILE environment variable.",
+				Optional: true,
+			},
+			"base_url": schema.StringAttribute{
+				Description: "Base URL of the Xata API serving databases. May contain the {workspace_id} and {region} placeholders, " +
+					"for instance https://{workspace_id}.{region}.xata.sh which is the default. Without placeholders the URL is also used " +
+					"for workspace management unless control_plane_url is set, which suits proxies and local stand-ins of the Xata API. " +
+					"May also be provided via XATA_BASE_URL environment variable.",
+				Optional: true,
+			},
+			"control_plane_url": schema.StringAttribute{
+				Description: "URL of the Xata API used for workspace management. Defaults to https://api.xata.io.",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Default region for new databases and for database endpoints when a resource does not specify one. " +
+					"May also be provided via XATA_REGION environment variable.",
+				Optional: true,
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Default workspace of the resources which do not set their workspace_id. May also be provided via XATA_WORKSPACE_ID environment variable, " +
+					"taken from the XATA_DATABASE_URL environment variable, or read from the Xata CLI profile.",
+				Optional: true,
+			},
+			"database": schema.StringAttribute{
+				Description: "Default database of the resources which do not set their database. May also be taken from the XATA_DATABASE_URL environment variable.",
+				Optional:    true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Default branch of the resources which do not set their branch. May also be provided via XATA_BRANCH environment variable, " +
+					"or taken from the XATA_DATABASE_URL environment variable. Defaults to main.",
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Maximum number of times a request rate limited or failed by the Xata API is retried. " +
+					"Requests which may have created something, such as the creation of a workspace, are only retried when rate limited. " +
+					"Defaults to " + strconv.Itoa(defaultMaxRetries) + ", 0 disables retries.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"min_retry_backoff": schema.StringAttribute{
+				Description: "Minimum wait before retrying a request, as a duration such as 500ms or 2s. Defaults to " + defaultMinRetryBackoff.String() + ".",
 				Optional:    true,
 			},
+			"max_retry_backoff": schema.StringAttribute{
+				Description: "Maximum wait before retrying a request, as a duration such as 10s or 1m. The wait doubles with each retry up to this value, " +
+					"a Retry-After header sent by the Xata API takes precedence within it. Defaults to " + defaultMaxRetryBackoff.String() + ".",
+				Optional: true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum rate of requests sent to the Xata API, shared by all resources and data sources. " +
+					"Requests over the rate wait for their turn. Defaults to 0, which disables the limit.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of requests in flight to the Xata API, shared by all resources and data sources. " +
+					"Lower it below the Terraform parallelism to stay within the Xata rate limits of schema operations. " +
+					"Data sources fetching the details of every listed item also run at most this many fetches at a time, 8 when unset. " +
+					"Defaults to 0, which disables the limit.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 		},
 	}
 }
@@ -85,6 +196,123 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		)
 	}
 
+	if config.ApikeyFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apikey_file"),
+			"Unknown Xata API Key File",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the Xata API Key file. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.Profile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown Xata CLI Profile",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the Xata CLI profile. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_PROFILE environment variable.",
+		)
+	}
+
+	if config.BaseURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_url"),
+			"Unknown Xata API Base URL",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the Xata API base URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_BASE_URL environment variable.",
+		)
+	}
+
+	if config.ControlPlaneURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("control_plane_url"),
+			"Unknown Xata API Control Plane URL",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the Xata API control plane URL. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.Region.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("region"),
+			"Unknown Xata Region",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the Xata region. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_REGION environment variable.",
+		)
+	}
+
+	if config.WorkspaceID.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("workspace_id"),
+			"Unknown Xata Workspace",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the default Xata workspace. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_WORKSPACE_ID environment variable.",
+		)
+	}
+
+	if config.Database.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Unknown Xata Database",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the default Xata database. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_DATABASE_URL environment variable.",
+		)
+	}
+
+	if config.Branch.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("branch"),
+			"Unknown Xata Branch",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the default Xata branch. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the XATA_BRANCH environment variable.",
+		)
+	}
+
+	if config.MaxRetries.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Unknown Xata API Max Retries",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the maximum number of retries. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.MinRetryBackoff.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("min_retry_backoff"),
+			"Unknown Xata API Min Retry Backoff",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the minimum retry backoff. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.MaxRetryBackoff.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retry_backoff"),
+			"Unknown Xata API Max Retry Backoff",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the maximum retry backoff. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.RequestsPerSecond.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Unknown Xata API Requests Per Second",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the maximum rate of requests. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.MaxConcurrentRequests.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_requests"),
+			"Unknown Xata API Max Concurrent Requests",
+			"The provider cannot create the Xata API client as there is an unknown configuration value for the maximum number of concurrent requests. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -93,9 +321,94 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	// with Terraform configuration value if set.
 
 	apikey := os.Getenv("XATA_API_KEY")
+	apikeySource := "XATA_API_KEY environment variable"
+
+	if !config.ApikeyFile.IsNull() {
+		content, err := os.ReadFile(config.ApikeyFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("apikey_file"),
+				"Unable to Read Xata API Key File",
+				"The provider cannot read the Xata API Key from the apikey_file: "+err.Error(),
+			)
+			return
+		}
+		apikey = strings.TrimSpace(string(content))
+		apikeySource = "apikey_file " + config.ApikeyFile.ValueString()
+	}
 
 	if !config.Apikey.IsNull() {
 		apikey = config.Apikey.ValueString()
+		apikeySource = "provider configuration"
+	}
+
+	baseURL := os.Getenv("XATA_BASE_URL")
+	region := os.Getenv("XATA_REGION")
+
+	if !config.BaseURL.IsNull() {
+		baseURL = config.BaseURL.ValueString()
+	}
+
+	if !config.Region.IsNull() {
+		region = config.Region.ValueString()
+	}
+
+	// Default workspace, database and branch of resources, with the parts of
+	// the database URL as fallbacks.
+
+	workspaceID := os.Getenv("XATA_WORKSPACE_ID")
+	database := ""
+	branch := os.Getenv("XATA_BRANCH")
+
+	if databaseURL := os.Getenv("XATA_DATABASE_URL"); databaseURL != "" {
+		parsed, err := parseDatabaseURL(databaseURL)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Xata Database URL",
+				"The provider cannot parse the XATA_DATABASE_URL environment variable: "+err.Error(),
+			)
+			return
+		}
+		workspaceID = cmp.Or(workspaceID, parsed.workspaceID)
+		region = cmp.Or(region, parsed.region)
+		database = parsed.database
+		branch = cmp.Or(branch, parsed.branch)
+	}
+
+	if !config.WorkspaceID.IsNull() {
+		workspaceID = config.WorkspaceID.ValueString()
+	}
+
+	if !config.Database.IsNull() {
+		database = config.Database.ValueString()
+	}
+
+	if !config.Branch.IsNull() {
+		branch = config.Branch.ValueString()
+	}
+
+	// Fall back to the profile of the Xata CLI credentials file for the
+	// settings set neither in the configuration nor in the environment.
+	// The default profile is only read when no API key is set otherwise.
+
+	profileName := os.Getenv("XATA_PROFILE")
+
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+
+	if profileName != "" || apikey == "" {
+		profile := loadProfile(ctx, profileName, &resp.Diagnostics)
+		if apikey == "" && profile.apiKey != "" {
+			apikey = profile.apiKey
+			apikeySource = "Xata CLI profile"
+		}
+		if region == "" {
+			region = profile.region
+		}
+		if workspaceID == "" {
+			workspaceID = profile.workspaceID
+		}
 	}
 
 	// If any of the expected configurations are missing, return
@@ -106,11 +419,43 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 			path.Root("apikey"),
 			"Missing Xata API Key",
 			"The provider cannot create the Xata API client as there is a missing or empty value for the Xata API Key. "+
-				"Set the password value in the configuration or use the XATA_API_KEY environment variable. "+
+				"Set the apikey value in the configuration, use the XATA_API_KEY environment variable, "+
+				"or log in with `xata auth login` to store it in the Xata CLI credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	if apikey != "" && !apikeyPattern.MatchString(apikey) {
+		resp.Diagnostics.AddError(
+			"Invalid Xata API Key",
+			"The Xata API Key read from the "+apikeySource+" is malformed, a Xata API key starts with xau_ followed by letters and digits. "+
+				"Ensure the value holds nothing but the key.",
+		)
+	}
+
+	controlPlaneURL, workspaceURL, err := resolveEndpoints(baseURL, config.ControlPlaneURL.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Xata API Endpoint",
+			"The provider cannot create the Xata API client as the configured endpoints are invalid: "+err.Error(),
+		)
+	}
+
+	maxRetries := defaultMaxRetries
+	if !config.MaxRetries.IsNull() {
+		maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	minRetryBackoff := parseBackoff(config.MinRetryBackoff, "min_retry_backoff", defaultMinRetryBackoff, &resp.Diagnostics)
+	maxRetryBackoff := parseBackoff(config.MaxRetryBackoff, "max_retry_backoff", defaultMaxRetryBackoff, &resp.Diagnostics)
+	if minRetryBackoff > maxRetryBackoff {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("min_retry_backoff"),
+			"Invalid Xata API Retry Backoff",
+			fmt.Sprintf("The minimum retry backoff %s exceeds the maximum retry backoff %s.", minRetryBackoff, maxRetryBackoff),
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -118,10 +463,29 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	ctx = tflog.SetField(ctx, "xata_apikey", apikey)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "xata_apikey")
 
-	tflog.Debug(ctx, "Creating Xata client")
+	tflog.Info(ctx, "Using Xata API key from "+apikeySource)
 
-	// Create a new Xata client using the configuration values
-	client, err := xata.NewWorkspacesClient(xata.WithAPIKey(apikey))
+	tflog.Debug(ctx, "Creating Xata client", map[string]any{
+		"xata_control_plane_url":       controlPlaneURL,
+		"xata_base_url":                workspaceURL,
+		"xata_region":                  region,
+		"xata_workspace_id":            workspaceID,
+		"xata_database":                database,
+		"xata_branch":                  branch,
+		"xata_max_retries":             maxRetries,
+		"xata_requests_per_second":     config.RequestsPerSecond.ValueFloat64(),
+		"xata_max_concurrent_requests": config.MaxConcurrentRequests.ValueInt64(),
+	})
+
+	// Throttle the requests of every client, each retry included, and retry
+	// rate limited and failed requests
+	throttle := newThrottleTransport(http.DefaultTransport, config.RequestsPerSecond.ValueFloat64(), int(config.MaxConcurrentRequests.ValueInt64()))
+	httpClient := &http.Client{
+		Transport: newRetryTransport(throttle, maxRetries, minRetryBackoff, maxRetryBackoff),
+	}
+
+	// Create new Xata clients using the configuration values
+	client, err := newXataClient(apikey, controlPlaneURL, workspaceURL, region, httpClient)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Xata API Client",
@@ -132,7 +496,12 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
-	// Make the Xata client available during DataSource and Resource
+	client.workspaceID = workspaceID
+	client.database = database
+	client.branch = branch
+	client.maxConcurrentRequests = int(config.MaxConcurrentRequests.ValueInt64())
+
+	// Make the Xata clients available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
@@ -140,10 +509,83 @@ func (p *xataProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	tflog.Info(ctx, "Configured Xata client", map[string]any{"success": true})
 }
 
+// loadProfile reads the named profile of the Xata CLI credentials file, the
+// default profile when name is empty. A missing default profile is not an
+// error, as the settings it provides may be set otherwise.
+func loadProfile(ctx context.Context, name string, diags *diag.Diagnostics) xataProfile {
+	explicit := name != ""
+	if !explicit {
+		name = defaultProfile
+	}
+
+	credentialsFile, err := credentialsPath()
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Read Xata CLI Credentials",
+			"The provider cannot locate the Xata CLI credentials file: "+err.Error(),
+		)
+		return xataProfile{}
+	}
+
+	profile, found, err := readProfile(credentialsFile, name)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Read Xata CLI Credentials",
+			"The provider cannot read the Xata CLI credentials file: "+err.Error(),
+		)
+		return xataProfile{}
+	}
+
+	if !found {
+		if explicit {
+			diags.AddAttributeError(
+				path.Root("profile"),
+				"Missing Xata CLI Profile",
+				fmt.Sprintf("The provider cannot find the profile %q in the Xata CLI credentials file %s. "+
+					"Log in with `xata auth login --profile %s` or set the profile value to an existing profile.", name, credentialsFile, name),
+			)
+		}
+		return xataProfile{}
+	}
+
+	tflog.Info(ctx, "Read Xata CLI profile", map[string]any{
+		"xata_profile":          name,
+		"xata_credentials_file": credentialsFile,
+	})
+
+	return profile
+}
+
+// parseBackoff parses the duration of a retry backoff attribute, returning
+// fallback when it is not set.
+func parseBackoff(value types.String, attribute string, fallback time.Duration, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() {
+		return fallback
+	}
+
+	backoff, err := time.ParseDuration(value.ValueString())
+	if err != nil || backoff < 0 {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid Xata API Retry Backoff",
+			fmt.Sprintf("Expected a non-negative duration such as 500ms or 30s. Got: %q", value.ValueString()),
+		)
+		return fallback
+	}
+
+	return backoff
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *xataProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewWorkspacesDataSource,
+		NewWorkspaceDataSource,
+		NewDatabasesDataSource,
+		NewBranchesDataSource,
+		NewBranchSchemaDataSource,
 	}
 }
 
@@ -151,5 +593,11 @@ func (p *xataProvider) DataSources(_ context.Context) []func() datasource.DataSo
 func (p *xataProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewWorkspaceResource,
+		NewDatabaseResource,
+		NewBranchResource,
+		NewTableResource,
+		NewBranchSchemaResource,
+		NewWorkspaceMemberResource,
+		NewWorkspaceInviteResource,
 	}
 }
@@ -4,21 +4,33 @@
 package provider
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/xataio/xata-go/xata"
 )
 
-const (
+var (
 	// providerConfig is a shared configuration to combine with the actual
 	// test configuration so the Xata client is properly configured.
-	// It is also possible to use the XATA_API_KEY	environment variable instead,
-	// such as updating the Makefile and running the testing through that tool.
+	// Acceptance tests run against an in-memory mock of the Xata API by
+	// default. Set XATA_TEST_LIVE to run them against the real Xata API,
+	// with the API key read from the XATA_API_KEY environment variable.
 	providerConfig = `
 	provider "xata" {}
 	`
-)
 
-var (
+	// testAccMock is the mock Xata API targeted by acceptance tests, or nil
+	// when they run against the real Xata API.
+	testAccMock *mockServer
+
 	// testAccProtoV6ProviderFactories are used to instantiate a provider during
 	// acceptance testing. The factory function will be invoked for every Terraform
 	// CLI command executed to create a provider server to which the CLI can
@@ -27,3 +39,217 @@ var (
 		"xata": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+func TestMain(m *testing.M) {
+	if os.Getenv("XATA_TEST_LIVE") != "" {
+		os.Exit(m.Run())
+	}
+
+	testAccMock = newMockServer()
+	providerConfig = testAccMock.providerConfig()
+
+	code := m.Run()
+	testAccMock.Close()
+	os.Exit(code)
+}
+
+func TestResolveEndpoints(t *testing.T) {
+	tests := []struct {
+		name             string
+		baseURL          string
+		controlPlaneURL  string
+		wantControlPlane string
+		wantWorkspace    string
+		wantErr          bool
+	}{
+		{
+			name:             "defaults",
+			wantControlPlane: "https://api.xata.io",
+			wantWorkspace:    "https://{workspace_id}.{region}.xata.sh",
+		},
+		{
+			name:             "single endpoint",
+			baseURL:          "http://127.0.0.1:8080/",
+			wantControlPlane: "http://127.0.0.1:8080",
+			wantWorkspace:    "http://127.0.0.1:8080",
+		},
+		{
+			name:             "staging template",
+			baseURL:          "https://{workspace_id}.{region}.staging-xata.dev",
+			controlPlaneURL:  "https://api.staging-xata.dev",
+			wantControlPlane: "https://api.staging-xata.dev",
+			wantWorkspace:    "https://{workspace_id}.{region}.staging-xata.dev",
+		},
+		{
+			name:             "template without control plane",
+			baseURL:          "https://proxy.example.com/{region}/{workspace_id}",
+			wantControlPlane: "https://api.xata.io",
+			wantWorkspace:    "https://proxy.example.com/{region}/{workspace_id}",
+		},
+		{
+			name:    "relative base URL",
+			baseURL: "xata.example.com",
+			wantErr: true,
+		},
+		{
+			name:            "invalid control plane URL",
+			controlPlaneURL: "ftp://api.example.com",
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controlPlane, workspace, err := resolveEndpoints(tt.baseURL, tt.controlPlaneURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if controlPlane != tt.wantControlPlane || workspace != tt.wantWorkspace {
+				t.Fatalf("resolveEndpoints() = %q, %q, want %q, %q", controlPlane, workspace, tt.wantControlPlane, tt.wantWorkspace)
+			}
+		})
+	}
+
+	client := &xataClient{baseURL: defaultWorkspaceURL, region: "eu-west-1"}
+	if got := client.workspaceURL("markspace-a1b2c3", ""); got != "https://markspace-a1b2c3.eu-west-1.xata.sh" {
+		t.Fatalf("workspaceURL() = %q", got)
+	}
+	if got := client.workspaceURL("markspace-a1b2c3", "us-east-1"); got != "https://markspace-a1b2c3.us-east-1.xata.sh" {
+		t.Fatalf("workspaceURL() = %q", got)
+	}
+}
+
+func TestWorkspaceClient(t *testing.T) {
+	client, err := newXataClient("xau_test", "https://api.xata.io", defaultWorkspaceURL, "eu-west-1", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diags diag.Diagnostics
+	if got := providerClient(client, &diags); got != client || diags.HasError() {
+		t.Fatalf("providerClient() = %p, %v", got, diags)
+	}
+	if got := providerClient("client", &diags); got != nil || !diags.HasError() {
+		t.Fatalf("providerClient() with unexpected type = %p, %v", got, diags)
+	}
+
+	diags = nil
+	clients, err := client.workspaceClients("markspace-a1b2c3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, ok := workspaceClient[xata.TableClient](client, "markspace-a1b2c3", "eu-west-1", &diags)
+	if !ok || tables != clients.tables {
+		t.Fatalf("workspaceClient[TableClient]() did not return the cached table client: %v", diags)
+	}
+	branches, ok := workspaceClient[xata.BranchClient](client, "markspace-a1b2c3", "eu-west-1", &diags)
+	if !ok || branches != clients.branches {
+		t.Fatalf("workspaceClient[BranchClient]() did not return the cached branch client: %v", diags)
+	}
+	records, ok := workspaceClient[xata.RecordsClient](client, "markspace-a1b2c3", "eu-west-1", &diags)
+	if !ok || records != clients.records {
+		t.Fatalf("workspaceClient[RecordsClient]() did not return the cached records client: %v", diags)
+	}
+	if _, ok := workspaceClient[xata.TableClient](nil, "markspace-a1b2c3", "", &diags); ok || !diags.HasError() {
+		t.Fatal("workspaceClient() accepted an unconfigured client")
+	}
+}
+
+func TestAPIKeyPattern(t *testing.T) {
+	for key, want := range map[string]bool{
+		"xau_mock":                             true,
+		"xau_4fG7hJ2kL9mN1pQ3rS5tU7vW9xY2zA4b": true,
+		"":                                     false,
+		"xau_":                                 false,
+		"4fG7hJ2kL9mN1pQ3rS5tU7vW9xY2zA4b":     false,
+		"xau_4fG7hJ2kL9mN1pQ3 ":                false,
+		"Bearer xau_4fG7hJ2kL9mN1pQ3":          false,
+		"xau_4fG7hJ2kL9mN1pQ3\nxau_5fG7":       false,
+	} {
+		if got := apikeyPattern.MatchString(key); got != want {
+			t.Errorf("apikeyPattern.MatchString(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestParseDatabaseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    databaseURL
+		wantErr bool
+	}{
+		{
+			raw:  "https://markspace-a1b2c3.eu-west-1.xata.sh/db/inventory:preview",
+			want: databaseURL{workspaceID: "markspace-a1b2c3", region: "eu-west-1", database: "inventory", branch: "preview"},
+		},
+		{
+			raw:  "https://markspace-a1b2c3.us-east-1.xata.sh/db/inventory",
+			want: databaseURL{workspaceID: "markspace-a1b2c3", region: "us-east-1", database: "inventory"},
+		},
+		{raw: "https://localhost:8080/db/inventory:main", wantErr: true},
+		{raw: "https://markspace-a1b2c3.eu-west-1.xata.sh/dbs/inventory", wantErr: true},
+		{raw: "https://markspace-a1b2c3.eu-west-1.xata.sh/db/:main", wantErr: true},
+		{raw: "https://markspace-a1b2c3.eu-west-1.xata.sh/db/inventory:main/tables/items", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseDatabaseURL(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDatabaseURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("parseDatabaseURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	client := &xataClient{workspaceID: "markspace-a1b2c3"}
+	if got := client.providerDefault("workspace_id"); got != "markspace-a1b2c3" {
+		t.Fatalf("providerDefault(workspace_id) = %q", got)
+	}
+	if got := client.providerDefault("database"); got != "" {
+		t.Fatalf("providerDefault(database) = %q", got)
+	}
+	if got := client.providerDefault("branch"); got != "main" {
+		t.Fatalf("providerDefault(branch) = %q", got)
+	}
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	const limit = 3
+
+	var inFlight, peak, calls atomic.Int32
+	err := forEachConcurrently(context.Background(), limit, 20, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 20 {
+		t.Errorf("fn was called %d times, want 20", calls.Load())
+	}
+	if peak.Load() > limit {
+		t.Errorf("%d calls were in flight, want at most %d", peak.Load(), limit)
+	}
+}
+
+func TestXataClientConcurrency(t *testing.T) {
+	if got := (&xataClient{}).concurrency(); got != defaultConcurrency {
+		t.Errorf("concurrency() = %d without max_concurrent_requests, want %d", got, defaultConcurrency)
+	}
+	if got := (&xataClient{maxConcurrentRequests: 2}).concurrency(); got != 2 {
+		t.Errorf("concurrency() = %d with max_concurrent_requests = 2, want 2", got)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultMaxRetries is the number of times a request is retried when
+	// max_retries is not configured.
+	defaultMaxRetries = 4
+
+	// defaultMinRetryBackoff and defaultMaxRetryBackoff bound the wait
+	// between retries when they are not configured.
+	defaultMinRetryBackoff = time.Second
+	defaultMaxRetryBackoff = 30 * time.Second
+)
+
+// retryTransport is an http.RoundTripper retrying requests rejected by the
+// Xata API because of rate limiting or transient server errors, waiting with
+// exponential backoff between attempts.
+//
+// Requests with an idempotent method are retried on 429 and 5xx responses
+// and on network errors. Other requests, such as the POST creating a
+// workspace, are only retried on 429 responses, which the API returns before
+// processing the request, so that a retry never creates a duplicate.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// sleep waits for the given duration or until the context is done.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport returns a retryTransport sending requests through base.
+func newRetryTransport(base http.RoundTripper, maxRetries int, minBackoff time.Duration, maxBackoff time.Duration) *retryTransport {
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		sleep:      sleepContext,
+	}
+}
+
+// RoundTrip sends the request, retrying it as long as it is safe to do so and
+// retries are left.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			// The previous attempt consumed the body.
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		fields := map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+		tflog.Debug(ctx, "Retrying Xata API request", fields)
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the outcome of an attempt warrants a retry.
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body cannot be sent again.
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// The request may have reached the API before the connection failed.
+		return idempotentMethod(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotentMethod(req.Method)
+	default:
+		return false
+	}
+}
+
+// backoff returns the wait before the retry following the given attempt. A
+// Retry-After header sent by the API takes precedence over the exponential
+// backoff, within maxBackoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(max(wait, t.minBackoff), t.maxBackoff)
+		}
+	}
+
+	wait := t.minBackoff
+	for i := 0; i < attempt && wait < t.maxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, t.maxBackoff)
+
+	// Add up to 25% of jitter so that concurrent requests spread out.
+	if jitter := int64(wait / 4); jitter > 0 {
+		wait += time.Duration(rand.Int63n(jitter))
+	}
+	return min(wait, t.maxBackoff)
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// idempotentMethod reports whether sending a request with the given method
+// twice has the same effect as sending it once.
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
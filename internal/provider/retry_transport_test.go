@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statuses     []int
+		retryAfter   string
+		wantStatus   int
+		wantAttempts int32
+		wantWaits    []time.Duration
+	}{
+		{
+			name:         "success",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 1,
+		},
+		{
+			name:         "rate limited then success",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusCreated},
+			wantStatus:   http.StatusCreated,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retry after header",
+			method:       http.MethodPut,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "3",
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+			wantWaits:    []time.Duration{3 * time.Second},
+		},
+		{
+			name:         "retry after capped by max backoff",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "3600",
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+			wantWaits:    []time.Duration{8 * time.Second},
+		},
+		{
+			name:         "server error on idempotent request",
+			method:       http.MethodDelete,
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusNoContent},
+			wantStatus:   http.StatusNoContent,
+			wantAttempts: 3,
+		},
+		{
+			name:         "server error on create is not retried",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusInternalServerError, http.StatusCreated},
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			name:         "client error is not retried",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries exhausted",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+				if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+					body, err := io.ReadAll(r.Body)
+					if err != nil || string(body) != `{"name":"markspace"}` {
+						t.Errorf("attempt %d sent body %q, %v", attempt, body, err)
+					}
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statuses[attempt-1])
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			transport := newRetryTransport(http.DefaultTransport, 2, time.Second, 8*time.Second)
+			transport.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			var body io.Reader
+			if tt.method != http.MethodGet && tt.method != http.MethodDelete {
+				body = strings.NewReader(`{"name":"markspace"}`)
+			}
+			req, err := http.NewRequest(tt.method, server.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus || attempts.Load() != tt.wantAttempts {
+				t.Fatalf("got status %d after %d attempts, want %d after %d", resp.StatusCode, attempts.Load(), tt.wantStatus, tt.wantAttempts)
+			}
+			if len(waits) != int(tt.wantAttempts)-1 {
+				t.Fatalf("waited %v", waits)
+			}
+			for i, want := range tt.wantWaits {
+				if waits[i] != want {
+					t.Fatalf("wait %d = %s, want %s", i, waits[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := newRetryTransport(http.DefaultTransport, 10, time.Second, 30*time.Second)
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second} {
+		got := transport.backoff(attempt, nil)
+		if got < want || got > min(want+want/4, 30*time.Second) {
+			t.Fatalf("backoff(%d) = %s, want %s plus up to 25%% jitter", attempt, got, want)
+		}
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got, ok := retryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now); !ok || got != 10*time.Second {
+		t.Fatalf("retryAfter(date) = %s, %v", got, ok)
+	}
+	if _, ok := retryAfter("soon", now); ok {
+		t.Fatal("retryAfter() accepted an invalid value")
+	}
+}
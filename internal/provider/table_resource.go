@@ -0,0 +1,970 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/xataio/xata-go/xata"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &tableResource{}
+	_ resource.ResourceWithConfigure      = &tableResource{}
+	_ resource.ResourceWithImportState    = &tableResource{}
+	_ resource.ResourceWithModifyPlan     = &tableResource{}
+	_ resource.ResourceWithValidateConfig = &tableResource{}
+)
+
+// columnTypes maps the column types accepted in configuration to the
+// xata-go column types.
+var columnTypes = map[string]xata.ColumnType{
+	"bool":     xata.ColumnTypeBool,
+	"int":      xata.ColumnTypeInt,
+	"float":    xata.ColumnTypeFloat,
+	"string":   xata.ColumnTypeString,
+	"text":     xata.ColumnTypeText,
+	"email":    xata.ColumnTypeEmail,
+	"multiple": xata.ColumnTypeMultiple,
+	"link":     xata.ColumnTypeLink,
+	"datetime": xata.ColumnTypeDatetime,
+	"vector":   xata.ColumnTypeVector,
+	"file":     xata.ColumnTypeFile,
+	"file[]":   xata.ColumnTypeFileMap,
+	"json":     xata.ColumnTypeJSON,
+	"object":   xata.ColumnTypeObject,
+}
+
+// NewTableResource is a helper function to simplify the provider implementation.
+func NewTableResource() resource.Resource {
+	return &tableResource{}
+}
+
+// tableResource is the resource implementation.
+type tableResource struct {
+	client *xataClient
+}
+
+// tableResourceModel maps the resource schema data.
+type tableResourceModel struct {
+	Id          types.String       `tfsdk:"id"`
+	WorkspaceId types.String       `tfsdk:"workspace_id"`
+	Database    types.String       `tfsdk:"database"`
+	Branch      types.String       `tfsdk:"branch"`
+	Name        types.String       `tfsdk:"name"`
+	Region      types.String       `tfsdk:"region"`
+	Columns     []tableColumnModel `tfsdk:"columns"`
+	LastUpdated types.String       `tfsdk:"last_updated"`
+	Timeouts    timeouts.Value     `tfsdk:"timeouts"`
+}
+
+// tableColumnModel maps table column schema data.
+type tableColumnModel struct {
+	Name                    types.String `tfsdk:"name"`
+	Type                    types.String `tfsdk:"type"`
+	NotNull                 types.Bool   `tfsdk:"not_null"`
+	Unique                  types.Bool   `tfsdk:"unique"`
+	DefaultValue            types.String `tfsdk:"default_value"`
+	LinkTable               types.String `tfsdk:"link_table"`
+	VectorDimension         types.Int64  `tfsdk:"vector_dimension"`
+	FileDefaultPublicAccess types.Bool   `tfsdk:"file_default_public_access"`
+}
+
+// Metadata returns the resource type name.
+func (r *tableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *tableResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *tableResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	typeNames := make([]string, 0, len(columnTypes))
+	for name := range columnTypes {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	resp.Schema = schema.Schema{
+		Description: "Manages a table and its columns in a database branch.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the table in the form workspace_id/database:branch/name.",
+				Computed:    true,
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace the database belongs to. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"database": schema.StringAttribute{
+				Description: "Name of the database the table belongs to. Defaults to the database of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Name of the branch the table belongs to. Defaults to the branch of the provider, main when unset.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the table. Changing it renames the table in place.",
+				Required:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region where the database of the table is hosted.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"columns": schema.SetNestedAttribute{
+				Description: "Columns of the table. Columns are matched by name: new columns are added, " +
+					"removed columns are dropped, and changes to not_null and default_value are applied in place " +
+					"through a pgroll migration when the database has Postgres enabled. Columns whose type, unique, " +
+					"link_table, vector_dimension or file_default_public_access changed, and changed columns of " +
+					"databases without Postgres enabled, are dropped and added again, which discards their data.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the column. Columns nested in an object column are prefixed with the name of the object column and a dot.",
+							Required:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "Type of the column. One of " + strings.Join(typeNames, ", ") + ".",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(typeNames...),
+							},
+						},
+						"not_null": schema.BoolAttribute{
+							Description: "Whether the column rejects null values.",
+							Optional:    true,
+						},
+						"unique": schema.BoolAttribute{
+							Description: "Whether the column values must be unique.",
+							Optional:    true,
+						},
+						"default_value": schema.StringAttribute{
+							Description: "Default value of the column.",
+							Optional:    true,
+						},
+						"link_table": schema.StringAttribute{
+							Description: "Table referenced by a link column.",
+							Optional:    true,
+						},
+						"vector_dimension": schema.Int64Attribute{
+							Description: "Dimension of a vector column.",
+							Optional:    true,
+						},
+						"file_default_public_access": schema.BoolAttribute{
+							Description: "Whether files of a file column are publicly accessible by default.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the table.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that columns carry the settings their type needs.
+func (r *tableResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var columnSet types.Set
+	diags := req.Config.GetAttribute(ctx, path.Root("columns"), &columnSet)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || columnSet.IsNull() || columnSet.IsUnknown() {
+		return
+	}
+
+	var columns []tableColumnModel
+	diags = columnSet.ElementsAs(ctx, &columns, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, column := range columns {
+		if column.Type.IsUnknown() || column.Name.IsUnknown() {
+			continue
+		}
+
+		switch columnType := column.Type.ValueString(); {
+		case columnType == "link" && column.LinkTable.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Missing Link Table",
+				fmt.Sprintf("Column %q has type link and must set link_table.", column.Name.ValueString()),
+			)
+		case columnType == "vector" && column.VectorDimension.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Missing Vector Dimension",
+				fmt.Sprintf("Column %q has type vector and must set vector_dimension.", column.Name.ValueString()),
+			)
+		case columnType != "link" && !column.LinkTable.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Unexpected Link Table",
+				fmt.Sprintf("Column %q sets link_table but has type %s.", column.Name.ValueString(), columnType),
+			)
+		case columnType != "vector" && !column.VectorDimension.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Unexpected Vector Dimension",
+				fmt.Sprintf("Column %q sets vector_dimension but has type %s.", column.Name.ValueString(), columnType),
+			)
+		case columnType != "file" && columnType != "file[]" && !column.FileDefaultPublicAccess.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Unexpected File Default Public Access",
+				fmt.Sprintf("Column %q sets file_default_public_access but has type %s.", column.Name.ValueString(), columnType),
+			)
+		}
+
+		if column.DefaultValue.IsNull() || column.DefaultValue.IsUnknown() {
+			continue
+		}
+		if _, err := sqlDefaultValue(column.Type.ValueString(), column.DefaultValue.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Invalid Default Value",
+				fmt.Sprintf("Column %q has type %s, but its %s.", column.Name.ValueString(), column.Type.ValueString(), err.Error()),
+			)
+		}
+	}
+
+	seen := make(map[string]bool, len(columns))
+	objects := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if column.Name.IsUnknown() {
+			continue
+		}
+		if seen[column.Name.ValueString()] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Duplicate Column Name",
+				fmt.Sprintf("Column %q is declared more than once.", column.Name.ValueString()),
+			)
+		}
+		seen[column.Name.ValueString()] = true
+		if column.Type.IsUnknown() || column.Type.ValueString() == "object" {
+			objects[column.Name.ValueString()] = true
+		}
+	}
+
+	// Nested columns need their parent to be declared as an object column
+	for _, column := range columns {
+		if column.Name.IsUnknown() {
+			continue
+		}
+		parent, _, nested := cutLast(column.Name.ValueString(), ".")
+		if nested && !objects[parent] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("columns"),
+				"Missing Object Column",
+				fmt.Sprintf("Column %q is nested in column %q, which must be declared with type object.", column.Name.ValueString(), parent),
+			)
+		}
+	}
+}
+
+// ModifyPlan sets the attributes left unset to the provider defaults and
+// warns about columns that will be dropped and added again because they
+// cannot be altered in place.
+func (r *tableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id", "database", "branch")
+
+	// Nothing to compare on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var columnSet types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("columns"), &columnSet)...)
+	if resp.Diagnostics.HasError() || columnSet.IsUnknown() {
+		return
+	}
+
+	var planColumns []tableColumnModel
+	var state tableResourceModel
+	var name types.String
+	resp.Diagnostics.Append(columnSet.ElementsAs(ctx, &planColumns, false)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Recreating columns needs the database, which the provider may not
+	// be configured to reach yet
+	if r.client == nil {
+		return
+	}
+	drop, _, add, err := r.columnChanges(ctx, state, planColumns)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata Database",
+			fmt.Sprintf("Could not read database %q, unexpected error: %s", state.Database.ValueString(), err.Error()),
+		)
+		return
+	}
+	added := make(map[string]bool, len(add))
+	for _, column := range add {
+		added[column.Name.ValueString()] = true
+	}
+	for _, column := range drop {
+		if added[column] {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("columns"),
+				"Column Will Be Recreated",
+				fmt.Sprintf("Column %q in table %q cannot be altered in place. Xata only alters not_null and default_value "+
+					"in place, and only in databases with Postgres enabled, so the column and the columns nested in it "+
+					"will be dropped and added again and their data will be lost.", column, name.ValueString()),
+			)
+		}
+	}
+}
+
+// Create a new resource.
+func (r *tableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan tableResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resolve the workspace API serving the database
+	region, found, err := r.client.databaseRegion(ctx, plan.WorkspaceId.ValueString(), plan.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata table",
+			fmt.Sprintf("Could not read database %q, unexpected error: %s", plan.Database.ValueString(), err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("database"),
+			"Error creating Xata table",
+			fmt.Sprintf("Database %q does not exist in workspace %q.", plan.Database.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.Region = types.StringValue(region)
+
+	tableClient, ok := workspaceClient[xata.TableClient](r.client, plan.WorkspaceId.ValueString(), region, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Create new table
+	_, err = tableClient.Create(ctx, plan.tableRequest())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata table",
+			fmt.Sprintf("Could not create table, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Track the table before adding columns, so that a failure leaves a
+	// tainted resource rather than a table unknown to Terraform
+	id := fmt.Sprintf("%s/%s:%s/%s", plan.WorkspaceId.ValueString(), plan.Database.ValueString(), plan.Branch.ValueString(), plan.Name.ValueString())
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), plan.WorkspaceId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), plan.Database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("branch"), plan.Branch)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), plan.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), plan.Region)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("timeouts"), plan.Timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Add the declared columns in name order, so that object columns
+	// precede their nested columns
+	columns := slices.Clone(plan.Columns)
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name.ValueString() < columns[j].Name.ValueString()
+	})
+	for _, column := range columns {
+		err = r.addColumn(ctx, tableClient, plan, column)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating Xata table",
+				fmt.Sprintf("Could not add column %q, unexpected error: %s", column.Name.ValueString(), err.Error()),
+			)
+			return
+		}
+	}
+
+	// Populate Computed attribute values from the created table
+	err = r.readTable(ctx, tableClient, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata table",
+			fmt.Sprintf("Could not read table after creation, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *tableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state tableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported tables do not know their region yet
+	if state.Region.IsNull() || state.Region.ValueString() == "" {
+		region, found, err := r.client.databaseRegion(ctx, state.WorkspaceId.ValueString(), state.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Xata table",
+				fmt.Sprintf("Could not read database %q, unexpected error: %s", state.Database.ValueString(), err.Error()),
+			)
+			return
+		}
+		if !found {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		state.Region = types.StringValue(region)
+	}
+
+	tableClient, ok := workspaceClient[xata.TableClient](r.client, state.WorkspaceId.ValueString(), state.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Get existing table
+	err := r.readTable(ctx, tableClient, &state)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata table",
+			fmt.Sprintf("Could not read table, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Return table info
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *tableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state
+	var plan, state tableResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceURL := r.client.workspaceURL(state.WorkspaceId.ValueString(), state.Region.ValueString())
+	dbBranchName := state.Database.ValueString() + ":" + state.Branch.ValueString()
+
+	// Work out the minimal set of column operations before changing
+	// anything, so that a database lookup or an invalid default value
+	// leaves the table untouched
+	drop, alter, add, err := r.columnChanges(ctx, state, plan.Columns)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Xata Table",
+			fmt.Sprintf("Could not read database %q, unexpected error: %s", state.Database.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	var operations []migrationOperation
+	for _, change := range alter {
+		operation, err := alterColumnOperation(
+			plan.Name.ValueString(), change.To.Name.ValueString(), change.To.Type.ValueString(),
+			change.From.settings(), change.To.settings(),
+		)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Table",
+				fmt.Sprintf("Could not alter column %q: %s", change.To.Name.ValueString(), err.Error()),
+			)
+			return
+		}
+		if operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+
+	// Rename the table if its name changed
+	if !plan.Name.Equal(state.Name) {
+		err := r.client.api.UpdateTable(ctx, workspaceURL, dbBranchName, state.Name.ValueString(), updateTableRequest{
+			Name: plan.Name.ValueString(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Table",
+				fmt.Sprintf("Could not rename table, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	tableClient, ok := workspaceClient[xata.TableClient](r.client, plan.WorkspaceId.ValueString(), plan.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Apply the column operations
+	for _, name := range drop {
+		_, err := tableClient.DeleteColumn(ctx, xata.DeleteColumnRequest{
+			TableRequest: plan.tableRequest(),
+			ColumnName:   name,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Table",
+				fmt.Sprintf("Could not drop column %q, unexpected error: %s", name, err.Error()),
+			)
+			return
+		}
+	}
+
+	if len(operations) > 0 {
+		err := r.client.api.MigrateBranch(ctx, workspaceURL, dbBranchName, operations)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Table",
+				fmt.Sprintf("Could not alter the columns, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	for _, column := range add {
+		err := r.addColumn(ctx, tableClient, plan, column)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Table",
+				fmt.Sprintf("Could not add column %q, unexpected error: %s", column.Name.ValueString(), err.Error()),
+			)
+			return
+		}
+	}
+
+	// Populate Computed attribute values from the updated table
+	err = r.readTable(ctx, tableClient, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata table",
+			fmt.Sprintf("Could not read table after update, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *tableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state tableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tableClient, ok := workspaceClient[xata.TableClient](r.client, state.WorkspaceId.ValueString(), state.Region.ValueString(), &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Delete table
+	_, err := tableClient.Delete(ctx, state.tableRequest())
+	// A table deleted outside Terraform is already gone
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Xata Table",
+			fmt.Sprintf("Could not delete table, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+}
+
+func (r *tableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID, database name, branch name and table name
+	parts := strings.Split(req.ID, "/")
+	var database, branch string
+	if len(parts) == 3 {
+		database, branch, _ = strings.Cut(parts[1], ":")
+	}
+	if len(parts) != 3 || parts[0] == "" || database == "" || branch == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/database:branch/table. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("branch"), branch)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[2])...)
+}
+
+// tableRequest returns the xata-go request addressing the table.
+func (m tableResourceModel) tableRequest() xata.TableRequest {
+	return xata.TableRequest{
+		DatabaseName: xata.String(m.Database.ValueString()),
+		BranchName:   xata.String(m.Branch.ValueString()),
+		TableName:    m.Name.ValueString(),
+	}
+}
+
+// addColumn adds a column to the table.
+func (r *tableResource) addColumn(ctx context.Context, tableClient xata.TableClient, table tableResourceModel, column tableColumnModel) error {
+	_, err := tableClient.AddColumn(ctx, xata.AddColumnRequest{
+		TableRequest: table.tableRequest(),
+		Column:       column.toAPI(),
+	})
+	return err
+}
+
+// columnChanges compares the columns of a table in state with the planned
+// ones like diffColumns. Columns are only altered in place when the database
+// of the table has Postgres enabled, as other databases do not support pgroll
+// migrations, and are dropped and added again otherwise.
+func (r *tableResource) columnChanges(ctx context.Context, state tableResourceModel, planned []tableColumnModel) ([]string, []columnChange, []tableColumnModel, error) {
+	drop, alter, add := diffColumns(state.Columns, planned, true)
+	if len(alter) == 0 {
+		return drop, alter, add, nil
+	}
+
+	database, err := r.client.api.GetDatabaseMetadata(ctx, state.WorkspaceId.ValueString(), state.Database.ValueString())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !database.PostgresEnabled {
+		drop, alter, add = diffColumns(state.Columns, planned, false)
+	}
+
+	return drop, alter, add, nil
+}
+
+// readTable fetches the columns of the table named in model and maps the
+// API response onto it.
+func (r *tableResource) readTable(ctx context.Context, tableClient xata.TableClient, model *tableResourceModel) error {
+	tableSchema, err := tableClient.GetSchema(ctx, model.tableRequest())
+	if err != nil {
+		return err
+	}
+
+	prior := make(map[string]tableColumnModel, len(model.Columns))
+	for _, column := range model.Columns {
+		prior[column.Name.ValueString()] = column
+	}
+
+	// Flatten the columns nested in object columns, naming them parent.child
+	var columns []tableColumnModel
+	pending := tableSchema.Columns
+	for len(pending) > 0 {
+		column := pending[0]
+		pending = pending[1:]
+
+		// Xata manages its internal columns itself
+		if strings.HasPrefix(column.Name, "xata.") || strings.HasPrefix(column.Name, "xata_") {
+			continue
+		}
+		if column.Columns != nil {
+			for _, child := range *column.Columns {
+				nested := *child
+				nested.Name = column.Name + "." + child.Name
+				pending = append(pending, &nested)
+			}
+		}
+
+		previous := prior[column.Name]
+		state := tableColumnModel{
+			Name:                    types.StringValue(column.Name),
+			Type:                    types.StringValue(column.Type.String()),
+			NotNull:                 boolFromAPI(column.NotNull, previous.NotNull),
+			Unique:                  boolFromAPI(column.Unique, previous.Unique),
+			DefaultValue:            types.StringPointerValue(column.DefaultValue),
+			LinkTable:               types.StringNull(),
+			VectorDimension:         types.Int64Null(),
+			FileDefaultPublicAccess: types.BoolNull(),
+		}
+		if column.Link != nil {
+			state.LinkTable = types.StringValue(column.Link.Table)
+		}
+		if column.Vector != nil {
+			state.VectorDimension = types.Int64Value(int64(column.Vector.Dimension))
+		}
+		if column.File != nil {
+			state.FileDefaultPublicAccess = boolFromAPI(column.File.DefaultPublicAccess, previous.FileDefaultPublicAccess)
+		}
+		if column.FileMap != nil {
+			state.FileDefaultPublicAccess = boolFromAPI(column.FileMap.DefaultPublicAccess, previous.FileDefaultPublicAccess)
+		}
+
+		columns = append(columns, state)
+	}
+
+	model.Id = types.StringValue(fmt.Sprintf("%s/%s:%s/%s", model.WorkspaceId.ValueString(), model.Database.ValueString(), model.Branch.ValueString(), model.Name.ValueString()))
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name.ValueString() < columns[j].Name.ValueString()
+	})
+	model.Columns = columns
+
+	return nil
+}
+
+// toAPI converts table column schema data to the xata-go representation.
+func (m tableColumnModel) toAPI() *xata.Column {
+	column := &xata.Column{
+		Name:         m.Name.ValueString(),
+		Type:         columnTypes[m.Type.ValueString()],
+		NotNull:      m.NotNull.ValueBoolPointer(),
+		Unique:       m.Unique.ValueBoolPointer(),
+		DefaultValue: m.DefaultValue.ValueStringPointer(),
+	}
+	if !m.LinkTable.IsNull() {
+		column.Link = &xata.ColumnLink{Table: m.LinkTable.ValueString()}
+	}
+	if !m.VectorDimension.IsNull() {
+		column.Vector = &xata.ColumnVector{Dimension: int(m.VectorDimension.ValueInt64())}
+	}
+	if !m.FileDefaultPublicAccess.IsNull() {
+		file := &xata.ColumnFile{DefaultPublicAccess: m.FileDefaultPublicAccess.ValueBoolPointer()}
+		if m.Type.ValueString() == "file[]" {
+			column.FileMap = file
+		} else {
+			column.File = file
+		}
+	}
+
+	return column
+}
+
+// equal reports whether two columns have the same definition. Unset flags
+// are treated as false, like the Xata API does.
+func (m tableColumnModel) equal(other tableColumnModel) bool {
+	return m.alterable(other) &&
+		m.NotNull.ValueBool() == other.NotNull.ValueBool() &&
+		m.DefaultValue.Equal(other.DefaultValue)
+}
+
+// alterable reports whether a column can be altered in place into other. The
+// link table, vector dimension and file access are part of the type, as Xata
+// cannot change them on an existing column. Uniqueness cannot change either,
+// as the Xata API does not expose the name of the unique constraint to drop.
+func (m tableColumnModel) alterable(other tableColumnModel) bool {
+	return m.Name.Equal(other.Name) &&
+		m.Type.Equal(other.Type) &&
+		m.Unique.ValueBool() == other.Unique.ValueBool() &&
+		m.LinkTable.Equal(other.LinkTable) &&
+		m.VectorDimension.Equal(other.VectorDimension) &&
+		m.FileDefaultPublicAccess.ValueBool() == other.FileDefaultPublicAccess.ValueBool()
+}
+
+// settings returns the settings of the column a migration can alter.
+func (m tableColumnModel) settings() columnSettings {
+	return columnSettings{
+		NotNull:      m.NotNull.ValueBool(),
+		DefaultValue: m.DefaultValue.ValueStringPointer(),
+	}
+}
+
+// columnChange is a column whose settings change in place.
+type columnChange struct {
+	From tableColumnModel
+	To   tableColumnModel
+}
+
+// diffColumns compares the current columns of a table with the desired ones
+// and returns the names of the columns to drop, the columns to alter in
+// place and the columns to add. Columns which cannot be altered in place, or
+// every changed column when inPlace is false, are both dropped and added,
+// together with the columns nested in them. Columns nested in a
+// dropped column are not dropped on their own, and columns are added in name
+// order so that object columns precede their nested columns.
+func diffColumns(current []tableColumnModel, desired []tableColumnModel, inPlace bool) ([]string, []columnChange, []tableColumnModel) {
+	existing := make(map[string]tableColumnModel, len(current))
+	for _, column := range current {
+		existing[column.Name.ValueString()] = column
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, column := range desired {
+		wanted[column.Name.ValueString()] = true
+	}
+
+	// Find the columns that disappear, either removed or recreated
+	gone := make(map[string]bool, len(current))
+	for _, column := range current {
+		if !wanted[column.Name.ValueString()] {
+			gone[column.Name.ValueString()] = true
+		}
+	}
+	for _, column := range desired {
+		previous, ok := existing[column.Name.ValueString()]
+		if ok && (!previous.alterable(column) || !inPlace && !previous.equal(column)) {
+			gone[column.Name.ValueString()] = true
+		}
+	}
+
+	var drop []string
+	for name := range gone {
+		if !hasAncestorIn(name, gone) {
+			drop = append(drop, name)
+		}
+	}
+	sort.Strings(drop)
+
+	var alter []columnChange
+	var add []tableColumnModel
+	for _, column := range desired {
+		name := column.Name.ValueString()
+		previous, ok := existing[name]
+		switch {
+		case !ok || gone[name] || hasAncestorIn(name, gone):
+			add = append(add, column)
+		case !previous.equal(column):
+			alter = append(alter, columnChange{From: previous, To: column})
+		}
+	}
+	sort.Slice(alter, func(i, j int) bool {
+		return alter[i].To.Name.ValueString() < alter[j].To.Name.ValueString()
+	})
+	sort.Slice(add, func(i, j int) bool {
+		return add[i].Name.ValueString() < add[j].Name.ValueString()
+	})
+
+	return drop, alter, add
+}
+
+// hasAncestorIn reports whether one of the object columns name is nested in
+// belongs to set.
+func hasAncestorIn(name string, set map[string]bool) bool {
+	for i := range len(name) {
+		if name[i] == '.' && set[name[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// cutLast slices s around the last instance of sep, like strings.Cut.
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// boolFromAPI maps an optional flag returned by the Xata API. The API
+// reports unset flags as false, so false is only kept when it was already
+// explicitly set in the prior state.
+func boolFromAPI(value *bool, prior types.Bool) types.Bool {
+	if value != nil && *value {
+		return types.BoolValue(true)
+	}
+	if !prior.IsNull() && !prior.IsUnknown() && !prior.ValueBool() {
+		return types.BoolValue(false)
+	}
+
+	return types.BoolNull()
+}
@@ -0,0 +1,561 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccTableResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_table" "items" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "items"
+
+  columns = [
+    {
+      name          = "title"
+      type          = "string"
+      not_null      = true
+      default_value = "untitled"
+    },
+    {
+      name   = "sku"
+      type   = "string"
+      unique = true
+    },
+    {
+      name             = "embedding"
+      type             = "vector"
+      vector_dimension = 3
+    },
+    {
+      name = "dimensions"
+      type = "object"
+    },
+    {
+      name = "dimensions.width"
+      type = "float"
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify created table has Computed attributes filled.
+					resource.TestCheckResourceAttr("xata_table.items", "name", "items"),
+					resource.TestCheckResourceAttr("xata_table.items", "branch", "main"),
+					resource.TestCheckResourceAttr("xata_table.items", "region", "us-east-1"),
+					resource.TestCheckResourceAttr("xata_table.items", "columns.#", "5"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name": "dimensions.width",
+						"type": "float",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name":          "title",
+						"type":          "string",
+						"not_null":      "true",
+						"default_value": "untitled",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name":             "embedding",
+						"type":             "vector",
+						"vector_dimension": "3",
+					}),
+					resource.TestCheckResourceAttrSet("xata_table.items", "id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "xata_table.items",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The last_updated attribute does not exist in the Xata
+				// API, therefore there is no value for it during import.
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_table" "items" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "products"
+
+  columns = [
+    {
+      name          = "title"
+      type          = "text"
+      not_null      = true
+      default_value = "untitled"
+    },
+    {
+      name          = "sku"
+      type          = "string"
+      not_null      = true
+      default_value = "none"
+    },
+    {
+      name = "price"
+      type = "float"
+    },
+    {
+      name = "dimensions"
+      type = "object"
+    },
+    {
+      name          = "dimensions.width"
+      type          = "float"
+      default_value = "0"
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_table.items", "name", "products"),
+					resource.TestCheckResourceAttr("xata_table.items", "columns.#", "5"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name": "title",
+						"type": "text",
+					}),
+					// Recreated without the unique constraint
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name":          "sku",
+						"not_null":      "true",
+						"default_value": "none",
+					}),
+					// Recreated, as the database does not have Postgres enabled
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name":          "dimensions.width",
+						"default_value": "0",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name": "price",
+						"type": "float",
+					}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccTableResource_postgresEnabled(t *testing.T) {
+	// Databases cannot be created with Postgres enabled through the
+	// provider, the mock Xata API enables it directly.
+	if testAccMock == nil {
+		t.Skip("pgroll migrations are only tested against the mock Xata API")
+	}
+
+	config := func(column string) string {
+		return providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "catalog" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "catalog"
+  region       = "us-east-1"
+}
+
+resource "xata_table" "items" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.catalog.name
+  name         = "items"
+
+  columns = [` + column + `]
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+    {
+      name = "stock"
+      type = "int"
+    },
+`),
+			},
+			// Altered in place through a pgroll migration
+			{
+				PreConfig: func() {
+					if err := testAccMock.enablePostgres("catalog"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config(`
+    {
+      name          = "stock"
+      type          = "int"
+      not_null      = true
+      default_value = "0"
+    },
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.items", "columns.*", map[string]string{
+						"name":          "stock",
+						"not_null":      "true",
+						"default_value": "0",
+					}),
+					testAccCheckMockMigrated("catalog"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckMockMigrated checks that a pgroll migration was applied to the
+// main branch of a database of the mock Xata API.
+func testAccCheckMockMigrated(database string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		testAccMock.mu.Lock()
+		defer testAccMock.mu.Unlock()
+
+		for _, workspace := range testAccMock.workspaces {
+			if db, ok := workspace.databases[database]; ok {
+				if db.branches["main"].pgrollVersion == "" {
+					return fmt.Errorf("no pgroll migration was applied to database %q", database)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("database %q not found", database)
+	}
+}
+
+func TestAccTableResource_objectColumns(t *testing.T) {
+	config := func(columns string) string {
+		return providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_table" "parcels" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "parcels"
+
+  columns = [` + columns + `]
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Nested columns are flattened as parent.child
+			{
+				Config: config(`
+    {
+      name = "address"
+      type = "object"
+    },
+    {
+      name = "address.city"
+      type = "string"
+    },
+    {
+      name = "address.geo"
+      type = "object"
+    },
+    {
+      name = "address.geo.lat"
+      type = "float"
+    },
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_table.parcels", "columns.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.parcels", "columns.*", map[string]string{
+						"name": "address.geo",
+						"type": "object",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.parcels", "columns.*", map[string]string{
+						"name": "address.geo.lat",
+						"type": "float",
+					}),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xata_table.parcels",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Nested columns are added and dropped on their own
+			{
+				Config: config(`
+    {
+      name = "address"
+      type = "object"
+    },
+    {
+      name = "address.city"
+      type = "string"
+    },
+    {
+      name = "address.zip"
+      type = "string"
+    },
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_table.parcels", "columns.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.parcels", "columns.*", map[string]string{
+						"name": "address.zip",
+						"type": "string",
+					}),
+				),
+			},
+			// Changing the type of an object column recreates it without
+			// the columns nested in it
+			{
+				Config: config(`
+    {
+      name = "address"
+      type = "json"
+    },
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_table.parcels", "columns.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.parcels", "columns.*", map[string]string{
+						"name": "address",
+						"type": "json",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTableResource_columnFailure(t *testing.T) {
+	config := func(column string) string {
+		return providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_database" "inventory" {
+  workspace_id = xata_workspace.markspace.id
+  name         = "inventory"
+  region       = "us-east-1"
+}
+
+resource "xata_table" "events" {
+  workspace_id = xata_workspace.markspace.id
+  database     = xata_database.inventory.name
+  name         = "events"
+
+  columns = [
+    {
+      name = "` + column + `"
+      type = "string"
+    },
+  ]
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Xata rejects the column after the table is created
+			{
+				Config:      config("xata_kind"),
+				ExpectError: regexp.MustCompile(`Could not add column "xata_kind"`),
+			},
+			// The table was tracked, so it is replaced rather than
+			// created again
+			{
+				Config: config("kind"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_table.events", "columns.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("xata_table.events", "columns.*", map[string]string{
+						"name": "kind",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTableResource_missingObjectColumn(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "xata_table" "parcels" {
+  workspace_id = "markspace-a1b2c3"
+  database     = "inventory"
+  name         = "parcels"
+
+  columns = [
+    {
+      name = "address"
+      type = "json"
+    },
+    {
+      name = "address.city"
+      type = "string"
+    },
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing Object Column`),
+			},
+		},
+	})
+}
+
+func TestHasAncestorIn(t *testing.T) {
+	set := map[string]bool{"address": true, "meta.tags": true}
+	tests := map[string]bool{
+		"address":          false,
+		"address.city":     true,
+		"address.geo.lat":  true,
+		"addresses.city":   false,
+		"meta":             false,
+		"meta.tags.colour": true,
+		"meta.note":        false,
+	}
+	for name, want := range tests {
+		if got := hasAncestorIn(name, set); got != want {
+			t.Errorf("hasAncestorIn(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestAccTableResource_invalidDefaultValue(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "xata_table" "items" {
+  workspace_id = "markspace-a1b2c3"
+  database     = "inventory"
+  name         = "items"
+
+  columns = [
+    {
+      name          = "count"
+      type          = "int"
+      default_value = "0); DROP TABLE items; --"
+    },
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Default Value`),
+			},
+		},
+	})
+}
+
+func TestDiffColumns(t *testing.T) {
+	column := func(name string, columnType string) tableColumnModel {
+		return tableColumnModel{
+			Name:                    types.StringValue(name),
+			Type:                    types.StringValue(columnType),
+			NotNull:                 types.BoolNull(),
+			Unique:                  types.BoolNull(),
+			DefaultValue:            types.StringNull(),
+			LinkTable:               types.StringNull(),
+			VectorDimension:         types.Int64Null(),
+			FileDefaultPublicAccess: types.BoolNull(),
+		}
+	}
+
+	explicitFalse := column("sku", "string")
+	explicitFalse.Unique = types.BoolValue(false)
+	required := column("code", "string")
+	required.NotNull = types.BoolValue(true)
+	unique := column("email", "email")
+	unique.Unique = types.BoolValue(true)
+
+	current := []tableColumnModel{
+		column("title", "string"), column("sku", "string"), column("legacy", "int"), column("code", "string"),
+		column("address", "object"), column("address.city", "string"), column("address.zip", "int"),
+		column("meta", "object"), column("meta.tag", "string"), column("email", "email"),
+	}
+	desired := []tableColumnModel{
+		column("title", "text"), explicitFalse, column("price", "float"), required,
+		column("address", "json"), column("meta", "object"), column("meta.tag", "string"), column("meta.note", "text"),
+		unique,
+	}
+
+	drop, alter, add := diffColumns(current, desired, true)
+
+	if want := []string{"address", "email", "legacy", "title"}; !reflect.DeepEqual(drop, want) {
+		t.Errorf("unexpected dropped columns: got %v, want %v", drop, want)
+	}
+
+	var altered []string
+	for _, change := range alter {
+		altered = append(altered, change.From.Name.ValueString()+":"+change.To.Name.ValueString())
+	}
+	if want := []string{"code:code"}; !reflect.DeepEqual(altered, want) {
+		t.Errorf("unexpected altered columns: got %v, want %v", altered, want)
+	}
+
+	var added []string
+	for _, column := range add {
+		added = append(added, column.Name.ValueString())
+	}
+	if want := []string{"address", "email", "meta.note", "price", "title"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("unexpected added columns: got %v, want %v", added, want)
+	}
+
+	// Without pgroll migrations, altered columns are recreated
+	drop, alter, _ = diffColumns(current, desired, false)
+	if want := []string{"address", "code", "email", "legacy", "title"}; !reflect.DeepEqual(drop, want) {
+		t.Errorf("unexpected dropped columns without migrations: got %v, want %v", drop, want)
+	}
+	if len(alter) != 0 {
+		t.Errorf("unexpected altered columns without migrations: %v", alter)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// throttleTransport is an http.RoundTripper keeping the requests sent to the
+// Xata API under a rate, with a token bucket, and under a number of requests
+// in flight. Terraform runs operations in parallel, so a single transport is
+// shared by every client of the provider.
+type throttleTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+// newThrottleTransport returns a throttleTransport sending requests through
+// base. A requestsPerSecond or maxConcurrent of 0 disables the corresponding
+// limit.
+func newThrottleTransport(base http.RoundTripper, requestsPerSecond float64, maxConcurrent int) *throttleTransport {
+	t := &throttleTransport{base: base}
+	if requestsPerSecond > 0 {
+		burst := max(int(math.Ceil(requestsPerSecond)), 1)
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+	if maxConcurrent > 0 {
+		t.slots = make(chan struct{}, maxConcurrent)
+	}
+	return t
+}
+
+// RoundTrip waits for a request slot and a token before sending the request.
+// The slot is held until the response body is read to the end or closed.
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	release := func() {}
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		default:
+			tflog.Debug(ctx, "Throttling Xata API request, waiting for a request slot", map[string]any{
+				"method":                  req.Method,
+				"url":                     req.URL.Redacted(),
+				"max_concurrent_requests": cap(t.slots),
+			})
+			select {
+			case t.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		release = func() { <-t.slots }
+	}
+
+	if t.limiter != nil {
+		reservation := t.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			tflog.Debug(ctx, "Throttling Xata API request, waiting for the rate limit", map[string]any{
+				"method":              req.Method,
+				"url":                 req.URL.Redacted(),
+				"requests_per_second": float64(t.limiter.Limit()),
+				"wait":                delay.String(),
+			})
+			if err := sleepContext(ctx, delay); err != nil {
+				reservation.Cancel()
+				release()
+				return nil, err
+			}
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	if t.slots != nil {
+		resp.Body = &slotBody{ReadCloser: resp.Body, release: release}
+	}
+	return resp, nil
+}
+
+// slotBody is a response body giving its request slot back once it has been
+// read to the end or closed, whichever comes first.
+type slotBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *slotBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *slotBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestThrottleTransportConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	base := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		current := inFlight.Add(1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport := newThrottleTransport(base, 0, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "http://xata.test/workspaces", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Fatalf("peak of %d requests in flight, want 2", got)
+	}
+}
+
+func TestThrottleTransportHoldsSlotUntilBodyIsDone(t *testing.T) {
+	base := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	transport := newThrottleTransport(base, 0, 2)
+	send := func(ctx context.Context) (*http.Response, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://xata.test/workspaces", nil)
+		return transport.RoundTrip(req)
+	}
+
+	// Two responses whose bodies are still open take both slots
+	first, err := send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := send(ctx); err == nil {
+		t.Fatal("RoundTrip() did not wait for a slot while both bodies were open")
+	}
+
+	// Reading a body to the end gives its slot back, closing it afterwards
+	// does not give it back twice
+	if _, err := io.ReadAll(first.Body); err != nil {
+		t.Fatal(err)
+	}
+	first.Body.Close()
+	third, err := send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := send(ctx); err == nil {
+		t.Fatal("RoundTrip() did not wait for a slot after a body was released twice")
+	}
+
+	// Closing a body without reading it gives its slot back
+	second.Body.Close()
+	third.Body.Close()
+	for i := 0; i < 2; i++ {
+		resp, err := send(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+	}
+}
+
+func TestThrottleTransportReleasesSlotOnError(t *testing.T) {
+	base := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	transport := newThrottleTransport(base, 0, 1)
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://xata.test/workspaces", nil)
+		_, err := transport.RoundTrip(req)
+		cancel()
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("RoundTrip() = %v, want the transport error", err)
+		}
+	}
+}
+
+func TestThrottleTransportRate(t *testing.T) {
+	var requests atomic.Int32
+	base := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		requests.Add(1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport := newThrottleTransport(base, 20, 0)
+
+	start := time.Now()
+	for i := 0; i < 30; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "http://xata.test/workspaces", nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A burst of 20 requests goes out at once, the next 10 at 20 per second.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("sent 30 requests in %s, want at least 400ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://xata.test/workspaces", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("RoundTrip() ignored the canceled context")
+	}
+	if got := requests.Load(); got != 30 {
+		t.Fatalf("sent %d requests, want 30", got)
+	}
+}
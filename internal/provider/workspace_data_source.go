@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                     = &workspaceDataSource{}
+	_ datasource.DataSourceWithConfigure        = &workspaceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &workspaceDataSource{}
+)
+
+// workspaceDataSourceModel maps the data source schema data.
+type workspaceDataSourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Slug        types.String `tfsdk:"slug"`
+	Plan        types.String `tfsdk:"plan"`
+	MemberCount types.Int64  `tfsdk:"membercount"`
+	Role        types.String `tfsdk:"role"`
+}
+
+// workspaceDataSource is the data source implementation.
+type workspaceDataSource struct {
+	client *xataClient
+}
+
+// NewWorkspaceDataSource is a helper function to simplify the provider implementation.
+func NewWorkspaceDataSource() datasource.DataSource {
+	return &workspaceDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *workspaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a workspace of the caller by identifier, slug or name. Exactly one of them must be set.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the workspace.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"slug": schema.StringAttribute{
+				Description: "Slug identifier of the workspace.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the workspace. Looking a workspace up by name fails when several workspaces of the caller share it.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"plan": schema.StringAttribute{
+				Description: "Tier of the workspace.",
+				Computed:    true,
+			},
+			"membercount": schema.Int64Attribute{
+				Description: "Number of members of the workspace.",
+				Computed:    true,
+			},
+			"role": schema.StringAttribute{
+				Description: "Role of the caller in the workspace.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ConfigValidators requires exactly one lookup attribute.
+func (d *workspaceDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("slug"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *workspaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state workspaceDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The list of workspaces is the only source of the caller's role
+	workspaceresponse, err := d.client.workspaces.List(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read User Workspaces",
+			err.Error(),
+		)
+		return
+	}
+
+	attribute, value, match := workspaceLookup(state)
+	matches := workspaceresponse.Workspaces[:0:0]
+	for _, workspace := range workspaceresponse.Workspaces {
+		if match(workspace.Id, workspace.Slug, workspace.Name) {
+			matches = append(matches, workspace)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Xata workspace not found",
+			fmt.Sprintf("No workspace of the caller has the %s %q.", attribute, value),
+		)
+		return
+	case 1:
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, workspace := range matches {
+			ids = append(ids, workspace.Id)
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Multiple Xata workspaces found",
+			fmt.Sprintf("%d workspaces of the caller have the %s %q: %s. Look the workspace up by id or slug instead.",
+				len(matches), attribute, value, strings.Join(ids, ", ")),
+		)
+		return
+	}
+	workspace := matches[0]
+
+	// The member count is only returned by the workspace endpoint
+	workspaceInfo, err := d.client.workspaces.GetWithWorkspaceID(ctx, workspace.Id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Workspace",
+			fmt.Sprintf("Could not read workspace %q, unexpected error: %s", workspace.Id, err.Error()),
+		)
+		return
+	}
+
+	// Map response body to model
+	state.Id = types.StringValue(workspace.Id)
+	state.Name = types.StringValue(workspace.Name)
+	state.Slug = types.StringValue(workspace.Slug)
+	state.Plan = types.StringValue(workspace.Plan.String())
+	state.Role = types.StringValue(workspace.Role.String())
+	state.MemberCount = types.Int64Value(int64(workspaceInfo.MemberCount))
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// workspaceLookup returns the lookup attribute set in the configuration, its
+// value, and a predicate matching the workspaces against it.
+func workspaceLookup(config workspaceDataSourceModel) (string, string, func(id, slug, name string) bool) {
+	switch {
+	case !config.Id.IsNull():
+		id := config.Id.ValueString()
+		return "id", id, func(candidate, _, _ string) bool { return candidate == id }
+	case !config.Slug.IsNull():
+		slug := config.Slug.ValueString()
+		return "slug", slug, func(_, candidate, _ string) bool { return candidate == slug }
+	default:
+		name := config.Name.ValueString()
+		return "name", name, func(_, _, candidate string) bool { return candidate == name }
+	}
+}
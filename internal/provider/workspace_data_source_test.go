@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWorkspaceDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Lookup by id, slug and name
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+  slug = "markspace-lookup"
+}
+
+data "xata_workspace" "by_id" {
+  id = xata_workspace.markspace.id
+}
+
+data "xata_workspace" "by_slug" {
+  slug = xata_workspace.markspace.slug
+}
+
+data "xata_workspace" "by_name" {
+  name = xata_workspace.markspace.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.xata_workspace.by_id", "slug", "xata_workspace.markspace", "slug"),
+					resource.TestCheckResourceAttr("data.xata_workspace.by_id", "name", "markspace"),
+					resource.TestCheckResourceAttr("data.xata_workspace.by_id", "plan", "free"),
+					resource.TestCheckResourceAttr("data.xata_workspace.by_id", "membercount", "1"),
+					resource.TestCheckResourceAttr("data.xata_workspace.by_id", "role", "owner"),
+					resource.TestCheckResourceAttrPair("data.xata_workspace.by_slug", "id", "xata_workspace.markspace", "id"),
+					resource.TestCheckResourceAttrPair("data.xata_workspace.by_name", "id", "xata_workspace.markspace", "id"),
+				),
+			},
+			// No match
+			{
+				Config: providerConfig + `
+data "xata_workspace" "missing" {
+  slug = "no-such-workspace"
+}
+`,
+				ExpectError: regexp.MustCompile(`No workspace of the caller has the slug "no-such-workspace"`),
+			},
+			// Several lookup attributes
+			{
+				Config: providerConfig + `
+data "xata_workspace" "ambiguous" {
+  slug = "markspace-lookup"
+  name = "markspace"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
@@ -0,0 +1,408 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &workspaceInviteResource{}
+	_ resource.ResourceWithConfigure   = &workspaceInviteResource{}
+	_ resource.ResourceWithImportState = &workspaceInviteResource{}
+	_ resource.ResourceWithModifyPlan  = &workspaceInviteResource{}
+)
+
+// Statuses of a workspace invite.
+const (
+	inviteStatusPending   = "pending"
+	inviteStatusExpired   = "expired"
+	inviteStatusAccepted  = "accepted"
+	inviteStatusCancelled = "cancelled"
+)
+
+// NewWorkspaceInviteResource is a helper function to simplify the provider implementation.
+func NewWorkspaceInviteResource() resource.Resource {
+	return &workspaceInviteResource{}
+}
+
+// workspaceInviteResource is the resource implementation.
+type workspaceInviteResource struct {
+	client *xataClient
+}
+
+// workspaceInviteResourceModel maps the resource schema data.
+type workspaceInviteResourceModel struct {
+	Id            types.String   `tfsdk:"id"`
+	WorkspaceId   types.String   `tfsdk:"workspace_id"`
+	Email         types.String   `tfsdk:"email"`
+	Role          types.String   `tfsdk:"role"`
+	ResendTrigger types.String   `tfsdk:"resend_trigger"`
+	InviteId      types.String   `tfsdk:"invite_id"`
+	Expires       types.String   `tfsdk:"expires"`
+	Status        types.String   `tfsdk:"status"`
+	LastUpdated   types.String   `tfsdk:"last_updated"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *workspaceInviteResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_invite"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *workspaceInviteResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *workspaceInviteResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Invites a user to join a workspace. Destroying the resource cancels the invite. " +
+			"Once the invite has been accepted or has expired it is removed from the state, " +
+			"manage accepted members with xata_workspace_member.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the invite in the form workspace_id/invite_id.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"email": schema.StringAttribute{
+				Description: "Email address of the invited user.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				Description: "Role granted to the user once the invite is accepted. One of " + strings.Join(workspaceRoles, ", ") + ".",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspaceRoles...),
+				},
+			},
+			"resend_trigger": schema.StringAttribute{
+				Description: "Arbitrary value, changing it sends the invite email again.",
+				Optional:    true,
+			},
+			"invite_id": schema.StringAttribute{
+				Description: "Identifier of the invite.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expires": schema.StringAttribute{
+				Description: "Timestamp of the expiry of the invite.",
+				Computed:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the invite. Only pending invites are kept in the state.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the invite.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ModifyPlan sets the workspace left unset to the provider default and
+// keeps the expiry of the invite unless the invite is resent, which extends
+// it.
+func (r *workspaceInviteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id")
+
+	// Nothing to do on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan workspaceInviteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if inviteResent(state, plan) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires"), state.Expires)...)
+}
+
+// Create a new resource.
+func (r *workspaceInviteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan workspaceInviteResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the invite
+	invite, err := r.client.api.InviteWorkspaceMember(ctx, plan.WorkspaceId.ValueString(), inviteWorkspaceMemberRequest{
+		Email: plan.Email.ValueString(),
+		Role:  plan.Role.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata workspace invite",
+			fmt.Sprintf("Could not invite %q, unexpected error: %s", plan.Email.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	// Map response body to schema and populate Computed attribute values
+	plan.Id = types.StringValue(plan.WorkspaceId.ValueString() + "/" + invite.InviteID)
+	plan.InviteId = types.StringValue(invite.InviteID)
+	plan.Expires = types.StringValue(invite.Expires.Format(time.RFC3339))
+	plan.Status = types.StringValue(inviteStatusPending)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *workspaceInviteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state workspaceInviteResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := r.client.api.ListWorkspaceMembers(ctx, state.WorkspaceId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata workspace invite",
+			fmt.Sprintf("Could not read workspace invites, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	invite, status := workspaceInviteStatus(members, state.InviteId.ValueString(), state.Email.ValueString(), time.Now())
+	if status != inviteStatusPending {
+		resp.Diagnostics.AddWarning(
+			"Xata workspace invite is no longer pending",
+			fmt.Sprintf("The invite of %q to workspace %q has been %s and is removed from the state. "+
+				"Terraform will plan to send a new invite if it is still configured.",
+				state.Email.ValueString(), state.WorkspaceId.ValueString(), status),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state.Id = types.StringValue(state.WorkspaceId.ValueString() + "/" + invite.InviteID)
+	state.Email = types.StringValue(invite.Email)
+	state.Role = types.StringValue(invite.Role)
+	state.Expires = types.StringValue(invite.Expires.Format(time.RFC3339))
+	state.Status = types.StringValue(status)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *workspaceInviteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state
+	var plan, state workspaceInviteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceID := plan.WorkspaceId.ValueString()
+	inviteID := state.InviteId.ValueString()
+
+	// Update the role granted by the invite
+	if !plan.Role.Equal(state.Role) {
+		_, err := r.client.api.UpdateWorkspaceInvite(ctx, workspaceID, inviteID, updateWorkspaceInviteRequest{
+			Role: plan.Role.ValueString(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Workspace Invite",
+				fmt.Sprintf("Could not update workspace invite, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	// Send the invite email again
+	if inviteResent(state, plan) {
+		err := r.client.api.ResendWorkspaceInvite(ctx, workspaceID, inviteID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Workspace Invite",
+				fmt.Sprintf("Could not resend workspace invite, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+
+		members, err := r.client.api.ListWorkspaceMembers(ctx, workspaceID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Updating Xata Workspace Invite",
+				fmt.Sprintf("Could not read workspace invites, unexpected error: %s", err.Error()),
+			)
+			return
+		}
+		if invite, status := workspaceInviteStatus(members, inviteID, plan.Email.ValueString(), time.Now()); status == inviteStatusPending {
+			plan.Expires = types.StringValue(invite.Expires.Format(time.RFC3339))
+		}
+	}
+
+	if plan.Expires.IsUnknown() {
+		plan.Expires = state.Expires
+	}
+	plan.InviteId = state.InviteId
+	plan.Status = types.StringValue(inviteStatusPending)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *workspaceInviteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state workspaceInviteResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Cancel the invite, which may have been accepted in the meantime
+	err := r.client.api.CancelWorkspaceInvite(ctx, state.WorkspaceId.ValueString(), state.InviteId.ValueString())
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Xata Workspace Invite",
+			fmt.Sprintf("Could not cancel workspace invite, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+}
+
+func (r *workspaceInviteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID and invite ID
+	workspaceID, inviteID, ok := strings.Cut(req.ID, "/")
+	if !ok || workspaceID == "" || inviteID == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/invite_id. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("invite_id"), inviteID)...)
+}
+
+// inviteResent reports whether the plan asks to send the invite email again.
+func inviteResent(state workspaceInviteResourceModel, plan workspaceInviteResourceModel) bool {
+	return !plan.ResendTrigger.IsNull() && !plan.ResendTrigger.Equal(state.ResendTrigger)
+}
+
+// workspaceInviteStatus finds an invite among the invites and members of a
+// workspace and returns it together with its status. An invite that is no
+// longer listed was accepted if a member with the invited email exists, and
+// cancelled otherwise.
+func workspaceInviteStatus(members *workspaceMembers, inviteID string, email string, now time.Time) (*workspaceInvite, string) {
+	for _, invite := range members.Invites {
+		if invite.InviteID != inviteID {
+			continue
+		}
+		if !invite.Expires.IsZero() && invite.Expires.Before(now) {
+			return &invite, inviteStatusExpired
+		}
+		return &invite, inviteStatusPending
+	}
+
+	for _, member := range members.Members {
+		if email != "" && strings.EqualFold(member.Email, email) {
+			return nil, inviteStatusAccepted
+		}
+	}
+
+	return nil, inviteStatusCancelled
+}
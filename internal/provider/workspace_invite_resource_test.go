@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWorkspaceInviteResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace_invite" "contractor" {
+  workspace_id = xata_workspace.markspace.id
+  email        = "contractor@example.com"
+  role         = "maintainer"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace_invite.contractor", "email", "contractor@example.com"),
+					resource.TestCheckResourceAttr("xata_workspace_invite.contractor", "role", "maintainer"),
+					resource.TestCheckResourceAttr("xata_workspace_invite.contractor", "status", "pending"),
+					resource.TestCheckResourceAttrSet("xata_workspace_invite.contractor", "invite_id"),
+					resource.TestCheckResourceAttrSet("xata_workspace_invite.contractor", "expires"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xata_workspace_invite.contractor",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace_invite" "contractor" {
+  workspace_id   = xata_workspace.markspace.id
+  email          = "contractor@example.com"
+  role           = "owner"
+  resend_trigger = "1"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace_invite.contractor", "role", "owner"),
+					resource.TestCheckResourceAttr("xata_workspace_invite.contractor", "status", "pending"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestWorkspaceInviteStatus(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	members := &workspaceMembers{
+		Members: []workspaceMember{
+			{UserID: "usr_1", Email: "Accepted@example.com", Role: "maintainer"},
+		},
+		Invites: []workspaceInvite{
+			{InviteID: "inv_pending", Email: "pending@example.com", Role: "owner", Expires: now.Add(time.Hour)},
+			{InviteID: "inv_expired", Email: "expired@example.com", Role: "owner", Expires: now.Add(-time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		inviteID string
+		email    string
+		want     string
+	}{
+		{name: "pending", inviteID: "inv_pending", email: "pending@example.com", want: inviteStatusPending},
+		{name: "expired", inviteID: "inv_expired", email: "expired@example.com", want: inviteStatusExpired},
+		{name: "accepted", inviteID: "inv_accepted", email: "accepted@example.com", want: inviteStatusAccepted},
+		{name: "cancelled", inviteID: "inv_cancelled", email: "cancelled@example.com", want: inviteStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invite, got := workspaceInviteStatus(members, tt.inviteID, tt.email, now)
+			if got != tt.want {
+				t.Fatalf("status = %q, want %q", got, tt.want)
+			}
+			if (invite != nil) != (got == inviteStatusPending || got == inviteStatusExpired) {
+				t.Fatalf("unexpected invite %+v for status %q", invite, got)
+			}
+		})
+	}
+}
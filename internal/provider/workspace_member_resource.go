@@ -0,0 +1,339 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &workspaceMemberResource{}
+	_ resource.ResourceWithConfigure   = &workspaceMemberResource{}
+	_ resource.ResourceWithImportState = &workspaceMemberResource{}
+	_ resource.ResourceWithModifyPlan  = &workspaceMemberResource{}
+)
+
+// workspaceRoles lists the roles a user can hold in a workspace.
+var workspaceRoles = []string{"owner", "maintainer"}
+
+// NewWorkspaceMemberResource is a helper function to simplify the provider implementation.
+func NewWorkspaceMemberResource() resource.Resource {
+	return &workspaceMemberResource{}
+}
+
+// workspaceMemberResource is the resource implementation.
+type workspaceMemberResource struct {
+	client *xataClient
+}
+
+// workspaceMemberResourceModel maps the resource schema data.
+type workspaceMemberResourceModel struct {
+	Id          types.String   `tfsdk:"id"`
+	WorkspaceId types.String   `tfsdk:"workspace_id"`
+	UserId      types.String   `tfsdk:"user_id"`
+	Role        types.String   `tfsdk:"role"`
+	Email       types.String   `tfsdk:"email"`
+	Fullname    types.String   `tfsdk:"fullname"`
+	LastUpdated types.String   `tfsdk:"last_updated"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *workspaceMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_member"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *workspaceMemberResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Schema defines the schema for the resource.
+func (r *workspaceMemberResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the role of a member of a workspace. The user must already have joined the workspace, " +
+			"use xata_workspace_invite to invite new users. Destroying the resource removes the member from the workspace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the membership in the form workspace_id/user_id.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "Identifier of the workspace. Defaults to the workspace_id of the provider.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				Description: "Identifier of the user.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				Description: "Role of the user in the workspace. One of " + strings.Join(workspaceRoles, ", ") + ".",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspaceRoles...),
+				},
+			},
+			"email": schema.StringAttribute{
+				Description: "Email address of the user.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fullname": schema.StringAttribute{
+				Description: "Full name of the user.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the membership.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ModifyPlan sets the attributes left unset to the provider defaults.
+func (r *workspaceMemberResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	applyProviderDefaults(ctx, r.client, req, resp, "workspace_id")
+}
+
+// Create a new resource.
+func (r *workspaceMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan workspaceMemberResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only existing members can be managed. The lookup reports the current
+	// role, keep the configured one to apply it below
+	role := plan.Role
+	found, err := r.readMember(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata workspace member",
+			fmt.Sprintf("Could not read workspace members, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_id"),
+			"Error creating Xata workspace member",
+			fmt.Sprintf("User %q is not a member of workspace %q. Invite the user with the xata_workspace_invite resource first.",
+				plan.UserId.ValueString(), plan.WorkspaceId.ValueString()),
+		)
+		return
+	}
+	plan.Role = role
+
+	// Set the member role
+	err = r.client.api.UpdateWorkspaceMemberRole(ctx, plan.WorkspaceId.ValueString(), plan.UserId.ValueString(), updateWorkspaceMemberRoleRequest{
+		Role: plan.Role.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating Xata workspace member",
+			fmt.Sprintf("Could not set workspace member role, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	plan.Id = types.StringValue(plan.WorkspaceId.ValueString() + "/" + plan.UserId.ValueString())
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *workspaceMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state workspaceMemberResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get existing member
+	found, err := r.readMember(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Xata workspace member",
+			fmt.Sprintf("Could not read workspace members, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Return member info
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource information.
+func (r *workspaceMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan workspaceMemberResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update the member role
+	err := r.client.api.UpdateWorkspaceMemberRole(ctx, plan.WorkspaceId.ValueString(), plan.UserId.ValueString(), updateWorkspaceMemberRoleRequest{
+		Role: plan.Role.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Xata Workspace Member",
+			fmt.Sprintf("Could not update workspace member role, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *workspaceMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state workspaceMemberResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := timeoutContext(ctx, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Remove member from the workspace
+	err := r.client.api.RemoveWorkspaceMember(ctx, state.WorkspaceId.ValueString(), state.UserId.ValueString())
+	// A member removed outside Terraform is already gone
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Xata Workspace Member",
+			fmt.Sprintf("Could not remove workspace member, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+}
+
+func (r *workspaceMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Split import ID into workspace ID and user ID
+	workspaceID, userID, ok := strings.Cut(req.ID, "/")
+	if !ok || workspaceID == "" || userID == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: workspace_id/user_id. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_id"), userID)...)
+}
+
+// readMember looks up the member named in model and maps the API response
+// onto it. It reports false if the user is not a member of the workspace.
+func (r *workspaceMemberResource) readMember(ctx context.Context, model *workspaceMemberResourceModel) (bool, error) {
+	members, err := r.client.api.ListWorkspaceMembers(ctx, model.WorkspaceId.ValueString())
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range members.Members {
+		if member.UserID != model.UserId.ValueString() {
+			continue
+		}
+
+		model.Id = types.StringValue(model.WorkspaceId.ValueString() + "/" + member.UserID)
+		model.Email = types.StringValue(member.Email)
+		model.Fullname = types.StringValue(member.Fullname)
+		if !model.Role.IsUnknown() {
+			model.Role = types.StringValue(member.Role)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
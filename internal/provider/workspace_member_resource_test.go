@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWorkspaceMemberResource(t *testing.T) {
+	// The member must already have joined the workspace, which cannot be
+	// arranged from Terraform alone. The mock Xata API adds the member
+	// directly, the real one needs an existing workspace member.
+	workspace := `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+`
+	workspaceID := "xata_workspace.markspace.id"
+	// The member joins as a maintainer and is created with another role, so
+	// that Create has to change it
+	userID := "usr_contractor"
+	preConfig := func() {
+		err := testAccMock.addMember("markspace", workspaceMember{
+			UserID:   userID,
+			Fullname: "Contractor",
+			Email:    "contractor@example.com",
+			Role:     "maintainer",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if testAccMock == nil {
+		workspace = ""
+		workspaceID = fmt.Sprintf("%q", os.Getenv("XATA_TEST_WORKSPACE_ID"))
+		userID = os.Getenv("XATA_TEST_USER_ID")
+		preConfig = func() {}
+		if workspaceID == `""` || userID == "" {
+			t.Skip("XATA_TEST_WORKSPACE_ID and XATA_TEST_USER_ID must be set for workspace member acceptance tests against the real Xata API")
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Workspace setup
+			{
+				Config: providerConfig + workspace,
+			},
+			// Create and Read testing
+			{
+				PreConfig: preConfig,
+				Config:    providerConfig + workspace + testAccWorkspaceMemberConfig(workspaceID, userID, "owner"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace_member.test", "user_id", userID),
+					resource.TestCheckResourceAttr("xata_workspace_member.test", "role", "owner"),
+					resource.TestCheckResourceAttrSet("xata_workspace_member.test", "id"),
+					resource.TestCheckResourceAttrSet("xata_workspace_member.test", "email"),
+					resource.TestCheckResourceAttrSet("xata_workspace_member.test", "last_updated"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "xata_workspace_member.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + workspace + testAccWorkspaceMemberConfig(workspaceID, userID, "maintainer"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace_member.test", "role", "maintainer"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccWorkspaceMemberConfig(workspaceID string, userID string, role string) string {
+	return fmt.Sprintf(`
+resource "xata_workspace_member" "test" {
+  workspace_id = %s
+  user_id      = %q
+  role         = %q
+}
+`, workspaceID, userID, role)
+}
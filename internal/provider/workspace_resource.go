@@ -6,13 +6,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/xataio/xata-go/xata"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -24,6 +30,12 @@ var (
 	_ resource.ResourceWithImportState = &workspaceResource{}
 )
 
+// workspacePlans lists the tiers a workspace can be on.
+var workspacePlans = []string{"free", "pro"}
+
+// workspaceSlugPattern matches the slugs accepted by the Xata API.
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 // NewWorkspaceResource is a helper function to simplify the provider implementation.
 func NewWorkspaceResource() resource.Resource {
 	return &workspaceResource{}
@@ -31,17 +43,18 @@ func NewWorkspaceResource() resource.Resource {
 
 // workspaceResource is the resource implementation.
 type workspaceResource struct {
-	client xata.WorkspacesClient
+	client *xataClient
 }
 
 // workspaceResourceModel maps the resource schema data.
 type workspaceResourceModel struct {
-	Name        types.String `tfsdk:"name"`
-	Slug        types.String `tfsdk:"slug"`
-	Id          types.String `tfsdk:"id"`
-	MemberCount types.Int64  `tfsdk:"membercount"`
-	Plan        types.String `tfsdk:"plan"`
-	LastUpdated types.String `tfsdk:"last_updated"`
+	Name        types.String   `tfsdk:"name"`
+	Slug        types.String   `tfsdk:"slug"`
+	Id          types.String   `tfsdk:"id"`
+	MemberCount types.Int64    `tfsdk:"membercount"`
+	Plan        types.String   `tfsdk:"plan"`
+	LastUpdated types.String   `tfsdk:"last_updated"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name.
@@ -51,28 +64,11 @@ func (r *workspaceResource) Metadata(_ context.Context, req resource.MetadataReq
 
 // Configure adds the provider configured client to the resource.
 func (r *workspaceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Add a nil check when handling ProviderData because Terraform
-	// sets that data after it calls the ConfigureProvider RPC.
-	if req.ProviderData == nil {
-		return
-	}
-
-	client, ok := req.ProviderData.(xata.WorkspacesClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected xata.WorkspacesClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
+	r.client = providerClient(req.ProviderData, &resp.Diagnostics)
 }
 
 // Schema defines the schema for the resource.
-func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *workspaceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a workspace.",
 		Attributes: map[string]schema.Attribute{
@@ -81,8 +77,16 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required:    true,
 			},
 			"slug": schema.StringAttribute{
-				Description: "Slug Identifier of the worskpace.",
-				Computed:    true,
+				Description: "Slug Identifier of the worskpace. Lowercase letters, digits and single hyphens. " +
+					"Defaults to a slug derived from the name, changing it updates the workspace in place.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(workspaceSlugPattern, "must contain only lowercase letters, digits and single hyphens"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"id": schema.StringAttribute{
 				Description: "Numeric Identifier of the worskpace.",
@@ -96,14 +100,30 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Computed:    true,
 			},
 			"plan": schema.StringAttribute{
-				Description: "Tier of the worskpace.",
-				Computed:    true,
+				Description: "Tier of the worskpace. One of " + strings.Join(workspacePlans, ", ") + ". " +
+					"Defaults to the tier assigned by Xata, upgrading requires a billing account.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspacePlans...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"last_updated": schema.StringAttribute{
 				Description: "Timestamp of the last Terraform update of the workspace.",
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -117,14 +137,23 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	// Generate API request body from plan
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan, letting the API derive the slug
+	// from the name when none is configured
 	workspaceRequest := xata.WorkspaceMeta{
 		Name: plan.Name.ValueString(),
-		Slug: xata.String(plan.Slug.ValueString()),
+	}
+	if !plan.Slug.IsUnknown() && !plan.Slug.IsNull() {
+		workspaceRequest.Slug = xata.String(plan.Slug.ValueString())
 	}
 
 	// Create new workspace
-	workspace, err := r.client.Create(ctx, &workspaceRequest)
+	workspace, err := r.client.workspaces.Create(ctx, &workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating Xata workspace",
@@ -133,12 +162,29 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	// Move the workspace to the requested tier
+	tier := workspace.Plan.String()
+	if !plan.Plan.IsUnknown() && plan.Plan.ValueString() != tier {
+		planDiags := r.updatePlan(ctx, workspace.Id, plan.Plan.ValueString())
+		if planDiags.HasError() {
+			planDiags.AddAttributeWarning(
+				path.Root("plan"),
+				"Xata workspace created on another tier",
+				fmt.Sprintf("Workspace %q was created on the %s tier. Terraform marks it as tainted, "+
+					"run terraform untaint to keep it and retry the tier change on the next apply.", workspace.Id, tier),
+			)
+		} else {
+			tier = plan.Plan.ValueString()
+		}
+		resp.Diagnostics.Append(planDiags...)
+	}
+
 	// Map response body to schema and populate Computed attribute values
 	plan.Name = types.StringValue(workspace.Name)
 	plan.Slug = types.StringValue(*workspace.Slug)
 	plan.Id = types.StringValue(workspace.Id)
 	plan.MemberCount = types.Int64Value(int64(workspace.MemberCount))
-	plan.Plan = types.StringValue(workspace.Plan.String())
+	plan.Plan = types.StringValue(tier)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	// Set state to fully populated data
@@ -151,16 +197,34 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 
 // Read resource information.
 func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Get workspace Id
-	var id types.String
-	diags := req.State.GetAttribute(ctx, path.Root("id"), &id)
+	// Get current state, which keeps attributes unknown to the Xata API
+	// such as last_updated
+	var workspace workspaceResourceModel
+	diags := req.State.Get(ctx, &workspace)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := timeoutContext(ctx, workspace.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := workspace.Id
+
 	// Get existing workspace for a given Id
-	workspaceInfo, err := r.client.GetWithWorkspaceID(ctx, id.ValueString())
+	workspaceInfo, err := r.client.workspaces.GetWithWorkspaceID(ctx, id.ValueString())
+	if isNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Xata workspace not found",
+			fmt.Sprintf("Workspace %q no longer exists and is removed from the state. "+
+				"Terraform will plan to create it again if it is still configured.", id.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Xata workspace",
@@ -169,14 +233,12 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Map API response to resource model
-	workspace := workspaceResourceModel{
-		Name:        types.StringValue(workspaceInfo.Name),
-		Slug:        types.StringValue(*workspaceInfo.Slug),
-		Id:          types.StringValue(workspaceInfo.Id),
-		MemberCount: types.Int64Value(int64(workspaceInfo.MemberCount)),
-		Plan:        types.StringValue((workspaceInfo.Plan.String())),
-	}
+	// Overwrite items with refreshed state
+	workspace.Name = types.StringValue(workspaceInfo.Name)
+	workspace.Slug = types.StringValue(*workspaceInfo.Slug)
+	workspace.Id = types.StringValue(workspaceInfo.Id)
+	workspace.MemberCount = types.Int64Value(int64(workspaceInfo.MemberCount))
+	workspace.Plan = types.StringValue(workspaceInfo.Plan.String())
 
 	// Return workspace info
 	diags = resp.State.Set(ctx, &workspace)
@@ -204,6 +266,12 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	ctx, cancel := timeoutContext(ctx, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	workspaceRequest := xata.UpdateWorkspaceRequest{
 		WorkspaceID: xata.String(id.ValueString()),
@@ -214,7 +282,7 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	// Update existing workspace
-	updatedWorkspace, err := r.client.Update(ctx, workspaceRequest)
+	updatedWorkspace, err := r.client.workspaces.Update(ctx, workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Xata Workspace",
@@ -223,12 +291,22 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	// Move the workspace to the requested tier
+	tier := updatedWorkspace.Plan.String()
+	if !plan.Plan.IsUnknown() && plan.Plan.ValueString() != tier {
+		resp.Diagnostics.Append(r.updatePlan(ctx, id.ValueString(), plan.Plan.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tier = plan.Plan.ValueString()
+	}
+
 	// Map response body to schema and populate Computed attribute values
 	plan.Name = types.StringValue(updatedWorkspace.Name)
 	plan.Slug = types.StringValue(*updatedWorkspace.Slug)
 	plan.Id = types.StringValue(id.ValueString())
 	plan.MemberCount = types.Int64Value(int64(updatedWorkspace.MemberCount))
-	plan.Plan = types.StringValue(updatedWorkspace.Plan.String())
+	plan.Plan = types.StringValue(tier)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	diags = resp.State.Set(ctx, plan)
@@ -243,13 +321,24 @@ func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteReque
 	var id types.String
 	diags := req.State.GetAttribute(ctx, path.Root("id"), &id)
 	resp.Diagnostics.Append(diags...)
+
+	// Get delete timeout
+	var timeoutsValue timeouts.Value
+	diags = req.State.GetAttribute(ctx, path.Root("timeouts"), &timeoutsValue)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Delete workspace
-	err := r.client.Delete(ctx, id.ValueString())
-	if err != nil {
+	ctx, cancel := timeoutContext(ctx, timeoutsValue.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete workspace, which may already have been deleted outside Terraform
+	err := r.client.workspaces.Delete(ctx, id.ValueString())
+	if err != nil && !isNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting Xata Workspace",
 			fmt.Sprintf("Could not delete workspace, unexpected error: %s", err.Error()),
@@ -262,3 +351,30 @@ func (r *workspaceResource) ImportState(ctx context.Context, req resource.Import
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// updatePlan moves a workspace to another tier. Billing failures are
+// reported against the plan attribute with a hint on how to resolve them.
+func (r *workspaceResource) updatePlan(ctx context.Context, workspaceID string, tier string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	err := r.client.api.UpdateWorkspacePlan(ctx, workspaceID, updateWorkspacePlanRequest{Plan: tier})
+	switch {
+	case err == nil:
+	case isBillingError(err):
+		diags.AddAttributeError(
+			path.Root("plan"),
+			"Xata workspace billing required",
+			fmt.Sprintf("Workspace %q cannot be moved to the %s tier because its billing is not set up. "+
+				"Add a payment method to the workspace in the Xata dashboard and apply again.\n\nAPI error: %s",
+				workspaceID, tier, err.Error()),
+		)
+	default:
+		diags.AddAttributeError(
+			path.Root("plan"),
+			"Error Updating Xata Workspace Plan",
+			fmt.Sprintf("Could not move workspace %q to the %s tier, unexpected error: %s", workspaceID, tier, err.Error()),
+		)
+	}
+
+	return diags
+}
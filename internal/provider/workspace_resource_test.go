@@ -4,9 +4,15 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/xataio/xata-go/xata"
 )
 
 func TestAccWorkspaceResource(t *testing.T) {
@@ -29,6 +35,13 @@ resource "xata_workspace" "markspace" {
 					resource.TestCheckResourceAttr("xata_workspace.markspace", "plan", "free"),
 				),
 			},
+			// Refresh testing, Terraform-only attributes survive a refresh
+			{
+				RefreshState: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("xata_workspace.markspace", "last_updated"),
+				),
+			},
 			// ImportState testing
 			{
 				ResourceName:      "xata_workspace.markspace",
@@ -48,13 +61,148 @@ resource "xata_workspace" "markspace" {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// Verify created workspace has Computed attributes filled.
 					resource.TestCheckResourceAttr("xata_workspace.markspace", "name", "narkspace"),
-					resource.TestCheckResourceAttr("xata_workspace.markspace", "slug", "narkspace"),
+					// Renaming keeps the slug derived at creation.
+					resource.TestCheckResourceAttr("xata_workspace.markspace", "slug", "markspace"),
 					// resource.TestCheckResourceAttr("xata_workspace.markspace", "id"),
 					resource.TestCheckResourceAttr("xata_workspace.markspace", "membercount", "1"),
 					resource.TestCheckResourceAttr("xata_workspace.markspace", "plan", "free"),
 				),
 			},
+			// Slug testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "narkspace"
+  slug = "narkspace"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace.markspace", "slug", "narkspace"),
+				),
+			},
+			// Plan testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "narkspace"
+  plan = "free"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace.markspace", "plan", "free"),
+				),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
+
+func TestAccWorkspaceResource_plan(t *testing.T) {
+	// Upgrading a real workspace needs a billing account, the mock Xata API
+	// upgrades it or refuses on demand.
+	if testAccMock == nil {
+		t.Skip("workspace plan changes are only tested against the mock Xata API")
+	}
+
+	config := func(plan string) string {
+		return providerConfig + fmt.Sprintf(`
+resource "xata_workspace" "billing" {
+  name = "billing"
+  plan = %q
+}
+`, plan)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("free"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace.billing", "plan", "free"),
+				),
+			},
+			// Upgrade testing
+			{
+				Config: config("pro"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace.billing", "plan", "pro"),
+				),
+			},
+			// Downgrade testing
+			{
+				Config: config("free"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("xata_workspace.billing", "plan", "free"),
+				),
+			},
+			// Billing error testing
+			{
+				PreConfig: func() {
+					if err := testAccMock.requireBilling("billing"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      config("pro"),
+				ExpectError: regexp.MustCompile(`Xata workspace billing required`),
+			},
+		},
+	})
+}
+
+func TestWorkspaceResourceUpdatePlan(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockServer()
+	defer mock.Close()
+
+	client, err := newXataClient(mockAPIKey, mock.URL, mock.URL, "", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace, err := client.workspaces.Create(ctx, &xata.WorkspaceMeta{Name: "billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &workspaceResource{client: client}
+
+	if diags := r.updatePlan(ctx, workspace.Id, "pro"); diags.HasError() {
+		t.Fatalf("updatePlan() = %v", diags)
+	}
+	details, err := client.api.GetWorkspace(ctx, workspace.Id)
+	if err != nil || details.Plan != "pro" {
+		t.Fatalf("GetWorkspace() = %+v, %v, want the pro plan", details, err)
+	}
+
+	if diags := r.updatePlan(ctx, workspace.Id, "free"); diags.HasError() {
+		t.Fatalf("updatePlan() = %v", diags)
+	}
+	if err := mock.requireBilling("billing"); err != nil {
+		t.Fatal(err)
+	}
+	diags := r.updatePlan(ctx, workspace.Id, "pro")
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Xata workspace billing required" {
+		t.Fatalf("updatePlan() = %v, want a billing diagnostic", diags)
+	}
+}
+
+func TestIsBillingError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "payment required", err: &apiError{StatusCode: http.StatusPaymentRequired}, want: true},
+		{name: "billing message", err: &apiError{StatusCode: http.StatusForbidden, Message: `{"message":"Billing account required"}`}, want: true},
+		{name: "wrapped", err: fmt.Errorf("update: %w", &apiError{StatusCode: http.StatusPaymentRequired}), want: true},
+		{name: "forbidden", err: &apiError{StatusCode: http.StatusForbidden, Message: `{"message":"forbidden"}`}, want: false},
+		{name: "server error", err: &apiError{StatusCode: http.StatusInternalServerError, Message: "payment service down"}, want: false},
+		{name: "network", err: errors.New("connection refused"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBillingError(tt.err); got != tt.want {
+				t.Fatalf("isBillingError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
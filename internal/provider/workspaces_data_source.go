@@ -5,38 +5,59 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/xataio/xata-go/xata"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource              = &workspacesDataSource{}
-	_ datasource.DataSourceWithConfigure = &workspacesDataSource{}
+	_ datasource.DataSource                   = &workspacesDataSource{}
+	_ datasource.DataSourceWithConfigure      = &workspacesDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &workspacesDataSource{}
 )
 
+// workspacesSortKeys lists the attributes workspaces can be sorted by.
+var workspacesSortKeys = []string{"name", "slug", "id"}
+
 // workspacesDataSourceModel maps the data source schema data.
 type workspacesDataSourceModel struct {
+	NameRegex  types.String      `tfsdk:"name_regex"`
+	SlugPrefix types.String      `tfsdk:"slug_prefix"`
+	Role       types.String      `tfsdk:"role"`
+	Plan       types.String      `tfsdk:"plan"`
+	SortBy     types.String      `tfsdk:"sort_by"`
 	Workspaces []workspacesModel `tfsdk:"workspaces"`
+	Ids        []types.String    `tfsdk:"ids"`
+	Slugs      []types.String    `tfsdk:"slugs"`
 }
 
 // workspacesModel maps workspaces schema data.
 type workspacesModel struct {
-	Id types.String `tfsdk:"id"`
-	//Unique_id   types.String              `tfsdk:"unique_id"`
-	Name types.String `tfsdk:"name"`
-	Slug types.String `tfsdk:"slug"`
-	Role types.String `tfsdk:"role"`
-	Plan types.String `tfsdk:"plan"`
+	Id          types.String `tfsdk:"id"`
+	UniqueId    types.String `tfsdk:"unique_id"`
+	Name        types.String `tfsdk:"name"`
+	Slug        types.String `tfsdk:"slug"`
+	Role        types.String `tfsdk:"role"`
+	Plan        types.String `tfsdk:"plan"`
+	MemberCount types.Int64  `tfsdk:"membercount"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	Settings    types.Map    `tfsdk:"settings"`
 }
 
 // workspacesDataSource is the data source implementation.
 type workspacesDataSource struct {
-	client xata.WorkspacesClient
+	client *xataClient
 }
 
 // NewWorkspacesDataSource is a helper function to simplify the provider implementation.
@@ -52,8 +73,48 @@ func (d *workspacesDataSource) Metadata(_ context.Context, req datasource.Metada
 // Schema defines the schema for the data source.
 func (d *workspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Fetches the list of workspaces.",
+		Description: "Fetches the list of workspaces, optionally filtered, sorted by name unless sort_by is set.",
 		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Description: "Regular expression, in RE2 syntax, the name of the workspaces must match.",
+				Optional:    true,
+			},
+			"slug_prefix": schema.StringAttribute{
+				Description: "Prefix the slug of the workspaces must start with.",
+				Optional:    true,
+			},
+			"role": schema.StringAttribute{
+				Description: "Role of the caller the workspaces must have. One of " + strings.Join(workspaceRoles, ", ") + ".",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspaceRoles...),
+				},
+			},
+			"plan": schema.StringAttribute{
+				Description: "Tier the workspaces must have. One of " + strings.Join(workspacePlans, ", ") + ".",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspacePlans...),
+				},
+			},
+			"sort_by": schema.StringAttribute{
+				Description: "Attribute the workspaces are sorted by, in ascending order with ties broken by id. One of " +
+					strings.Join(workspacesSortKeys, ", ") + ". Defaults to name.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspacesSortKeys...),
+				},
+			},
+			"ids": schema.ListAttribute{
+				Description: "Identifiers of the workspaces, in the order of workspaces.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"slugs": schema.ListAttribute{
+				Description: "Slug identifiers of the workspaces, in the order of workspaces.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 			"workspaces": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -62,9 +123,10 @@ func (d *workspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 							Description: "Numeric Identifier of each worskpace.",
 							Computed:    true,
 						},
-						//"unique_id": schema.StringAttribute{
-						//Computed: true,
-						//},
+						"unique_id": schema.StringAttribute{
+							Description: "Unique identifier of each worskpace, which unlike id is never reused.",
+							Computed:    true,
+						},
 						"name": schema.StringAttribute{
 							Description: "Name of each worskpace.",
 							Computed:    true,
@@ -81,6 +143,19 @@ func (d *workspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 							Description: "Tier of each worskpace.",
 							Computed:    true,
 						},
+						"membercount": schema.Int64Attribute{
+							Description: "Number of members of each worskpace.",
+							Computed:    true,
+						},
+						"created_at": schema.StringAttribute{
+							Description: "Creation timestamp of each worskpace.",
+							Computed:    true,
+						},
+						"settings": schema.MapAttribute{
+							Description: "Settings of each worskpace, with values other than strings encoded as JSON.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -88,32 +163,53 @@ func (d *workspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 	}
 }
 
-// Configure adds the provider configured client to the data source.
-func (d *workspacesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
-	// Add a nil check when handling ProviderData because Terraform
-	// sets that data after it calls the ConfigureProvider RPC.
-	if req.ProviderData == nil {
+// ValidateConfig checks that name_regex compiles.
+func (d *workspacesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var nameRegex types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name_regex"), &nameRegex)...)
+	if resp.Diagnostics.HasError() || nameRegex.IsNull() || nameRegex.IsUnknown() {
 		return
 	}
 
-	client, ok := req.ProviderData.(xata.WorkspacesClient)
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *xata.workspaceCli, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+	if _, err := regexp.Compile(nameRegex.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name_regex"),
+			"Invalid Regular Expression",
+			fmt.Sprintf("The name_regex value is not a valid regular expression: %s", err.Error()),
 		)
-
-		return
 	}
+}
 
-	d.client = client
+// Configure adds the provider configured client to the data source.
+func (d *workspacesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = providerClient(req.ProviderData, &resp.Diagnostics)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *workspacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state workspacesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	workspaceresponse, err := d.client.List(ctx)
+	// ValidateConfig skips name_regex when it is only known at apply time
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("The name_regex value is not a valid regular expression: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	workspaceresponse, err := d.client.workspaces.List(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read User Workspaces",
@@ -122,11 +218,17 @@ func (d *workspacesDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	// Map response body to model
+	// Map response body to model, keeping the workspaces matching the filters
 	for _, workspace := range workspaceresponse.Workspaces {
+		if nameRegex != nil && !nameRegex.MatchString(workspace.Name) ||
+			!strings.HasPrefix(workspace.Slug, state.SlugPrefix.ValueString()) ||
+			!state.Role.IsNull() && workspace.Role.String() != state.Role.ValueString() ||
+			!state.Plan.IsNull() && workspace.Plan.String() != state.Plan.ValueString() {
+			continue
+		}
+
 		workspaceState := workspacesModel{
-			Id: types.StringValue(workspace.Id),
-			// Unique_id:  types.StringValue(*workspace.Unique_id),
+			Id:   types.StringValue(workspace.Id),
 			Name: types.StringValue(workspace.Name),
 			Slug: types.StringValue(workspace.Slug),
 			Role: types.StringValue(workspace.Role.String()),
@@ -136,10 +238,94 @@ func (d *workspacesDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		state.Workspaces = append(state.Workspaces, workspaceState)
 	}
 
+	// The list omits the details of the workspaces, fetch them concurrently
+	err = forEachConcurrently(ctx, d.client.concurrency(), len(state.Workspaces), func(ctx context.Context, i int) error {
+		workspace := &state.Workspaces[i]
+		details, err := d.client.api.GetWorkspace(ctx, workspace.Id.ValueString())
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", workspace.Id.ValueString(), err)
+		}
+		return workspace.setDetails(ctx, details)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Xata Workspace Details",
+			err.Error(),
+		)
+		return
+	}
+
+	sortWorkspaces(state.Workspaces, state.SortBy.ValueString())
+
+	state.Ids = make([]types.String, 0, len(state.Workspaces))
+	state.Slugs = make([]types.String, 0, len(state.Workspaces))
+	for _, workspace := range state.Workspaces {
+		state.Ids = append(state.Ids, workspace.Id)
+		state.Slugs = append(state.Slugs, workspace.Slug)
+	}
+
 	// Set state
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+// setDetails maps the details of a workspace onto the model.
+func (m *workspacesModel) setDetails(ctx context.Context, details *workspaceDetails) error {
+	settings := make(map[string]string, len(details.Settings))
+	for key, value := range details.Settings {
+		if text, ok := value.(string); ok {
+			settings[key] = text
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		settings[key] = string(encoded)
+	}
+
+	settingsValue, diags := types.MapValueFrom(ctx, types.StringType, settings)
+	if diags.HasError() {
+		return fmt.Errorf("workspace %s settings: %v", m.Id.ValueString(), diags)
+	}
+
+	m.UniqueId = stringValueOrNull(details.UniqueID)
+	m.MemberCount = types.Int64Value(details.MemberCount)
+	m.CreatedAt = stringValueOrNull(details.CreatedAt)
+	m.Settings = settingsValue
+	return nil
+}
+
+// stringValueOrNull maps an empty string, which the API returns for fields
+// it does not set, to null.
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// sortWorkspaces sorts workspaces by the given attribute, name when empty,
+// breaking ties by id so that the order does not depend on the API.
+func sortWorkspaces(workspaces []workspacesModel, sortBy string) {
+	key := func(workspace workspacesModel) string {
+		switch sortBy {
+		case "slug":
+			return workspace.Slug.ValueString()
+		case "id":
+			return workspace.Id.ValueString()
+		default:
+			return workspace.Name.ValueString()
+		}
+	}
+
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		if ki, kj := key(workspaces[i]), key(workspaces[j]); ki != kj {
+			return ki < kj
+		}
+		return workspaces[i].Id.ValueString() < workspaces[j].Id.ValueString()
+	})
+}
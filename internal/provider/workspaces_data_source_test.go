@@ -4,29 +4,138 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
+func TestAccWorkspacesDataSource_nameRegexKnownAtApply(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The pattern is unknown at validation time, so it is first
+			// compiled when the data source is read
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+data "xata_workspaces" "test" {
+  name_regex = "${xata_workspace.markspace.id}["
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Regular Expression`),
+			},
+		},
+	})
+}
+
 func TestAccWorkspacesDataSource(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("the workspaces data source test expects the account to contain only the workspaces it creates, which requires the mock Xata API")
+	}
+
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Read testing
 			{
-				Config: providerConfig + `data "xata_workspaces" "test" {}`,
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace" "narkspace" {
+  name = "narkspace"
+}
+
+data "xata_workspaces" "test" {
+  depends_on = [xata_workspace.markspace, xata_workspace.narkspace]
+}
+`,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// Verify number of workspaces returned
-					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.#", "4"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.#", "2"),
 					// Verify the first workspace to ensure all attributes are set
-					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.id", "Tomiwa-Aribisala-s-workspace-tameub"),
-					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.name", "Tomiwa Aribisala's workspace"),
-					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.slug", "Tomiwa-Aribisala-s-workspace"),
+					resource.TestCheckResourceAttrPair("data.xata_workspaces.test", "workspaces.0.id", "xata_workspace.markspace", "id"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.name", "markspace"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.slug", "markspace"),
 					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.role", "owner"),
 					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.plan", "free"),
+					resource.TestCheckResourceAttrSet("data.xata_workspaces.test", "workspaces.0.unique_id"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.membercount", "1"),
+					resource.TestCheckResourceAttrSet("data.xata_workspaces.test", "workspaces.0.created_at"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.0.settings.postgresEnabled", "false"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "workspaces.1.name", "narkspace"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "ids.#", "2"),
+					resource.TestCheckResourceAttrPair("data.xata_workspaces.test", "ids.1", "xata_workspace.narkspace", "id"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.test", "slugs.0", "markspace"),
+				),
+			},
+			// Filter and sort testing
+			{
+				Config: providerConfig + `
+resource "xata_workspace" "markspace" {
+  name = "markspace"
+}
+
+resource "xata_workspace" "narkspace" {
+  name = "narkspace"
+  slug = "a-narkspace"
+}
+
+data "xata_workspaces" "sorted" {
+  sort_by    = "slug"
+  depends_on = [xata_workspace.markspace, xata_workspace.narkspace]
+}
+
+data "xata_workspaces" "filtered" {
+  name_regex  = "^n"
+  slug_prefix = "a-"
+  role        = "owner"
+  plan        = "free"
+  depends_on  = [xata_workspace.markspace, xata_workspace.narkspace]
+}
+
+data "xata_workspaces" "none" {
+  plan       = "pro"
+  depends_on = [xata_workspace.markspace, xata_workspace.narkspace]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.xata_workspaces.sorted", "slugs.#", "2"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.sorted", "slugs.0", "a-narkspace"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.sorted", "slugs.1", "markspace"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.filtered", "workspaces.#", "1"),
+					resource.TestCheckResourceAttrPair("data.xata_workspaces.filtered", "ids.0", "xata_workspace.narkspace", "id"),
+					resource.TestCheckResourceAttr("data.xata_workspaces.none", "ids.#", "0"),
 				),
 			},
 		},
 	})
 }
+
+func TestSortWorkspaces(t *testing.T) {
+	workspaces := []workspacesModel{
+		{Id: types.StringValue("ws-3"), Name: types.StringValue("b"), Slug: types.StringValue("a")},
+		{Id: types.StringValue("ws-2"), Name: types.StringValue("a"), Slug: types.StringValue("c")},
+		{Id: types.StringValue("ws-1"), Name: types.StringValue("b"), Slug: types.StringValue("b")},
+	}
+
+	for sortBy, want := range map[string][]string{
+		"":     {"ws-2", "ws-1", "ws-3"},
+		"name": {"ws-2", "ws-1", "ws-3"},
+		"slug": {"ws-3", "ws-1", "ws-2"},
+		"id":   {"ws-1", "ws-2", "ws-3"},
+	} {
+		sortWorkspaces(workspaces, sortBy)
+		for i, workspace := range workspaces {
+			if workspace.Id.ValueString() != want[i] {
+				t.Fatalf("sortWorkspaces(%q) = %v, want %v", sortBy, workspaces, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xataio/xata-go/xata"
+)
+
+func TestAlterColumnOperation(t *testing.T) {
+	cases := map[string]struct {
+		columnType string
+		from       columnSettings
+		to         columnSettings
+		want       string
+	}{
+		"unchanged": {
+			columnType: "string",
+			from:       columnSettings{NotNull: true},
+			to:         columnSettings{NotNull: true},
+			want:       `null`,
+		},
+		"not null with default": {
+			columnType: "string",
+			to:         columnSettings{NotNull: true, DefaultValue: xata.String("it's")},
+			want: `{"alter_column":{"column":"title","default":"'it''s'","down":"\"title\"","nullable":false,` +
+				`"table":"items","up":"COALESCE(\"title\", 'it''s')"}}`,
+		},
+		"nullable keeps default": {
+			columnType: "int",
+			from:       columnSettings{NotNull: true, DefaultValue: xata.String("1")},
+			to:         columnSettings{DefaultValue: xata.String("1")},
+			want:       `{"alter_column":{"column":"title","down":"\"title\"","nullable":true,"table":"items","up":"\"title\""}}`,
+		},
+		"drop default": {
+			columnType: "int",
+			from:       columnSettings{DefaultValue: xata.String("1")},
+			want:       `{"alter_column":{"column":"title","default":null,"table":"items"}}`,
+		},
+		"now default": {
+			columnType: "datetime",
+			to:         columnSettings{DefaultValue: xata.String("now")},
+			want:       `{"alter_column":{"column":"title","default":"now()","table":"items"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			operation, err := alterColumnOperation("items", "title", tc.columnType, tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(operation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("unexpected operation:\ngot  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAlterColumnOperationInvalidDefault(t *testing.T) {
+	_, err := alterColumnOperation("items", "count", "int", columnSettings{}, columnSettings{
+		NotNull: true, DefaultValue: xata.String("0); DROP TABLE items; --"),
+	})
+	if err == nil {
+		t.Fatal("expected an error for a default value that is not an int")
+	}
+}
+
+func TestSQLDefaultValue(t *testing.T) {
+	cases := []struct {
+		columnType string
+		value      string
+		want       string
+		wantErr    bool
+	}{
+		{columnType: "bool", value: "true", want: "true"},
+		{columnType: "bool", value: "1", want: "true"},
+		{columnType: "bool", value: "yes", wantErr: true},
+		{columnType: "int", value: "-42", want: "-42"},
+		{columnType: "int", value: "+7", want: "7"},
+		{columnType: "int", value: "0); DROP TABLE x; --", wantErr: true},
+		{columnType: "int", value: "1.5", wantErr: true},
+		{columnType: "float", value: "1.50", want: "1.5"},
+		{columnType: "float", value: "1e3", want: "1000"},
+		{columnType: "float", value: "0x1p-2", want: "0.25"},
+		{columnType: "float", value: "NaN", wantErr: true},
+		{columnType: "float", value: "Inf", wantErr: true},
+		{columnType: "float", value: "0 OR 1=1", wantErr: true},
+		{columnType: "datetime", value: "now", want: "now()"},
+		{columnType: "string", value: "it's", want: "'it''s'"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.columnType+" "+tc.value, func(t *testing.T) {
+			got, err := sqlDefaultValue(tc.columnType, tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("sqlDefaultValue() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Fatalf("sqlDefaultValue() = %q, %v, want %q", got, err, tc.want)
+			}
+		})
+	}
+}